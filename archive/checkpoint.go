@@ -0,0 +1,95 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// State of the export of a log group, saved after each exported page so that
+// an interrupted export can be resumed
+type Checkpoint struct {
+	Group        string   `json:"group"`
+	Since        int64    `json:"since"`
+	Until        int64    `json:"until"`
+	Filter       string   `json:"filter,omitempty"`
+	StreamNames  []string `json:"stream_names,omitempty"`
+	StreamPrefix *string  `json:"stream_prefix,omitempty"`
+	PerHour      bool     `json:"per_hour"`
+
+	// Token of the next page to export
+	NextToken *string `json:"next_token,omitempty"`
+	// Timestamp of the last exported event and ids of the exported events
+	// with that timestamp, used to resume once the token has expired
+	Timestamp int64    `json:"timestamp"`
+	LastIds   []string `json:"last_ids,omitempty"`
+	Events    int      `json:"events"`
+	// Size of the exported files when the checkpoint was saved, by file name
+	Files    map[string]int64 `json:"files"`
+	Complete bool             `json:"complete"`
+}
+
+// Record an exported event
+func (c *Checkpoint) Add(timestamp int64, id string) {
+	if timestamp != c.Timestamp {
+		c.Timestamp = timestamp
+		c.LastIds = nil
+	}
+	c.LastIds = append(c.LastIds, id)
+	c.Events++
+}
+
+// Whether the event was exported before the checkpoint, when resuming from
+// the checkpoint timestamp
+func (c *Checkpoint) Exported(timestamp int64, id string) bool {
+	if timestamp != c.Timestamp {
+		return false
+	}
+	for _, last := range c.LastIds {
+		if last == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Path of the checkpoint of the export of a group to the given directory
+func CheckpointPath(dir string, group string) string {
+	return filepath.Join(dir, BaseName(group)+".checkpoint.json")
+}
+
+// Load a checkpoint, returning nil if it does not exist
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("could not parse checkpoint '%s': %w", path, err)
+	}
+	if checkpoint.Files == nil {
+		checkpoint.Files = map[string]int64{}
+	}
+	return checkpoint, nil
+}
+
+// Save the checkpoint replacing the previous one at once, so that it is never
+// left partially written
+func (c *Checkpoint) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
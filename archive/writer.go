@@ -0,0 +1,132 @@
+package archive
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ravvio/awst/ui/tlog"
+)
+
+// Layout of the hour in the names of hourly files
+const HourLayout = "2006-01-02T15"
+
+// Base name of the files of a group, without the leading slash and with the
+// other slashes replaced. A short hash of the group name follows, so that
+// groups such as /a/b and a_b have distinct files.
+func BaseName(group string) string {
+	sum := sha256.Sum256([]byte(group))
+	name := strings.ReplaceAll(strings.TrimPrefix(group, "/"), "/", "_")
+	return name + "-" + hex.EncodeToString(sum[:4])
+}
+
+type openFile struct {
+	file *os.File
+	gz   *gzip.Writer
+	enc  *json.Encoder
+}
+
+// Writer of the records of a group to gzip compressed NDJSON files, a single
+// one or one per hour of the event timestamps.
+// Records are written to a new gzip member of each file until Commit, so that
+// files can be truncated back to their size at the last commit and appended to
+// when resuming an export.
+type Writer struct {
+	Dir     string
+	Group   string
+	PerHour bool
+	// Size of the files at the last commit, by file name
+	Files map[string]int64
+
+	open map[string]*openFile
+}
+
+// Setup a writer of the given group, truncating the given files to their size
+// at the last commit
+func NewWriter(dir string, group string, perHour bool, files map[string]int64) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	for name, size := range files {
+		if err := os.Truncate(filepath.Join(dir, name), size); err != nil {
+			return nil, err
+		}
+	}
+	return &Writer{
+		Dir:     dir,
+		Group:   group,
+		PerHour: perHour,
+		Files:   files,
+		open:    map[string]*openFile{},
+	}, nil
+}
+
+// Name of the file of an event with the given timestamp
+func (w *Writer) FileName(timestamp int64) string {
+	if !w.PerHour {
+		return BaseName(w.Group) + ".ndjson.gz"
+	}
+	hour := time.UnixMilli(timestamp).UTC().Format(HourLayout)
+	return BaseName(w.Group) + "." + hour + ".ndjson.gz"
+}
+
+func (w *Writer) Write(record tlog.Record, timestamp int64) error {
+	name := w.FileName(timestamp)
+	f, ok := w.open[name]
+	if !ok {
+		// Files which were not committed belong to an interrupted attempt
+		flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		if _, committed := w.Files[name]; !committed {
+			flags |= os.O_TRUNC
+		}
+		file, err := os.OpenFile(filepath.Join(w.Dir, name), flags, 0o644)
+		if err != nil {
+			return err
+		}
+		gz := gzip.NewWriter(file)
+		f = &openFile{file: file, gz: gz, enc: json.NewEncoder(gz)}
+		w.open[name] = f
+	}
+	return f.enc.Encode(record)
+}
+
+// Complete the gzip members of the files written since the last commit, and
+// record their sizes
+func (w *Writer) Commit() error {
+	for name, f := range w.open {
+		if err := f.gz.Close(); err != nil {
+			return err
+		}
+		if err := f.file.Sync(); err != nil {
+			return err
+		}
+		info, err := f.file.Stat()
+		if err != nil {
+			return err
+		}
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+		w.Files[name] = info.Size()
+		delete(w.open, name)
+	}
+	return nil
+}
+
+// Close the files written since the last commit without committing them,
+// their uncommitted records are truncated when resuming
+func (w *Writer) Close() error {
+	var err error
+	for name, f := range w.open {
+		if closeErr := f.file.Close(); err == nil {
+			err = closeErr
+		}
+		delete(w.open, name)
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package archive_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ravvio/awst/archive"
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func readMessages(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	assert.NoError(t, err)
+
+	messages := []string{}
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		record := tlog.Record{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		messages = append(messages, record.Message)
+	}
+	assert.NoError(t, scanner.Err())
+	return messages
+}
+
+func TestWriterResume(t *testing.T) {
+	dir := t.TempDir()
+	group := "/ecs/api"
+
+	w, err := archive.NewWriter(dir, group, false, map[string]int64{})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(tlog.Record{Group: group, Message: "a"}, 0))
+	assert.NoError(t, w.Write(tlog.Record{Group: group, Message: "b"}, 0))
+	assert.NoError(t, w.Commit())
+
+	assert.NoError(t, w.Write(tlog.Record{Group: group, Message: "c"}, 0))
+	assert.NoError(t, w.Commit())
+	files := maps.Clone(w.Files)
+	// Written after the last checkpoint
+	assert.NoError(t, w.Write(tlog.Record{Group: group, Message: "d"}, 0))
+	assert.NoError(t, w.Commit())
+
+	// Resume from the second commit
+	w, err = archive.NewWriter(dir, group, false, files)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(tlog.Record{Group: group, Message: "e"}, 0))
+	assert.NoError(t, w.Commit())
+
+	assert.Equal(t, []string{"a", "b", "c", "e"}, readMessages(t, filepath.Join(dir, archive.BaseName(group)+".ndjson.gz")))
+}
+
+func TestWriterPerHour(t *testing.T) {
+	dir := t.TempDir()
+
+	base := archive.BaseName("/aws/lambda/fn")
+	w, err := archive.NewWriter(dir, "/aws/lambda/fn", true, map[string]int64{})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(tlog.Record{Message: "a"}, 1712926800000))
+	assert.NoError(t, w.Write(tlog.Record{Message: "b"}, 1712930399999))
+	assert.NoError(t, w.Write(tlog.Record{Message: "c"}, 1712930400000))
+	assert.NoError(t, w.Commit())
+
+	assert.Equal(t, map[string]int64{
+		base + ".2024-04-12T13.ndjson.gz": w.Files[base+".2024-04-12T13.ndjson.gz"],
+		base + ".2024-04-12T14.ndjson.gz": w.Files[base+".2024-04-12T14.ndjson.gz"],
+	}, w.Files)
+	assert.Equal(t, []string{"a", "b"}, readMessages(t, filepath.Join(dir, base+".2024-04-12T13.ndjson.gz")))
+	assert.Equal(t, []string{"c"}, readMessages(t, filepath.Join(dir, base+".2024-04-12T14.ndjson.gz")))
+}
+
+func TestWriterClose(t *testing.T) {
+	dir := t.TempDir()
+	group := "/ecs/api"
+
+	w, err := archive.NewWriter(dir, group, false, map[string]int64{})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(tlog.Record{Group: group, Message: "a"}, 0))
+	assert.NoError(t, w.Commit())
+	files := maps.Clone(w.Files)
+
+	// Records written after the last commit are dropped when resuming
+	assert.NoError(t, w.Write(tlog.Record{Group: group, Message: "b"}, 0))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, files, w.Files)
+
+	w, err = archive.NewWriter(dir, group, false, files)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(tlog.Record{Group: group, Message: "c"}, 0))
+	assert.NoError(t, w.Commit())
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"a", "c"}, readMessages(t, filepath.Join(dir, archive.BaseName(group)+".ndjson.gz")))
+}
+
+func TestBaseName(t *testing.T) {
+	assert.Regexp(t, `^aws_lambda_fn-[0-9a-f]{8}$`, archive.BaseName("/aws/lambda/fn"))
+
+	names := map[string]string{}
+	for _, group := range []string{"/a/b", "a/b", "a_b", "/a_b", "a/_b"} {
+		name := archive.BaseName(group)
+		_, ok := names[name]
+		assert.False(t, ok, group)
+		names[name] = group
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	path := archive.CheckpointPath(t.TempDir(), "/ecs/api")
+
+	c, err := archive.LoadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+
+	token := "token"
+	c = &archive.Checkpoint{Group: "/ecs/api", NextToken: &token, Files: map[string]int64{}}
+	c.Add(10, "1")
+	c.Add(20, "2")
+	c.Add(20, "3")
+	assert.NoError(t, c.Save(path))
+
+	loaded, err := archive.LoadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, c, loaded)
+	assert.Equal(t, 3, loaded.Events)
+	assert.True(t, loaded.Exported(20, "3"))
+	assert.False(t, loaded.Exported(10, "1"))
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+// Register flags used to confirm changes with confirmChanges
+func addConfirmFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("yes", "y", false, "apply changes without asking for confirmation")
+	cmd.Flags().Bool("dry-run", false, "only show the changes which would be applied")
+
+	cmd.MarkFlagsMutuallyExclusive("yes", "dry-run")
+}
+
+// Print the table of changes and ask whether to apply them, unless confirmed
+// or denied with the flags registered by addConfirmFlags. Confirmation is
+// required to apply changes when stdin is not a terminal.
+func confirmChanges(ctx context.Context, cmd *cobra.Command, table tables.Table, prompt string) bool {
+	printTable(table)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	utils.CheckErr(err)
+	if dryRun {
+		style.PrintInfo("Dry run, no changes applied")
+		return false
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	utils.CheckErr(err)
+	if yes {
+		return true
+	}
+
+	if stat, err := os.Stdin.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		utils.CheckErr(fmt.Errorf("confirmation required, use --yes to apply changes without a terminal"))
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", style.StyleWarning("%s", prompt))
+
+	// Read in the background so that interrupts are not blocked
+	answers := make(chan string, 1)
+	go func() {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			line = ""
+		}
+		answers <- line
+	}()
+
+	select {
+	case <-ctx.Done():
+		fmt.Fprintln(os.Stderr)
+		exitIfDone(ctx)
+	case answer := <-answers:
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true
+		}
+	}
+	style.PrintInfo("No changes applied")
+	return false
+}
+
+// Apply a change to each log group, reporting failures without stopping, and
+// exit with the partial status if any of them failed
+func applyToGroups(ctx context.Context, names []string, action string, apply func(name string) error) {
+	failed := 0
+	for i, name := range names {
+		style.PrintProgress("%s %d/%d: %s", action, i+1, len(names), name)
+		err := apply(name)
+		if err == nil {
+			continue
+		}
+		exitIfDone(ctx)
+		fmt.Fprintln(os.Stderr)
+		style.PrintError("%s: %s", name, utils.ErrorReason(err))
+		failed++
+	}
+	fmt.Fprintln(os.Stderr)
+
+	if failed > 0 {
+		style.PrintError("%s failed for %d out of %d groups", action, failed, len(names))
+		os.Exit(utils.EXIT_PARTIAL)
+	}
+	style.PrintInfo("%s done for %d groups", action, len(names))
+}
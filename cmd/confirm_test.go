@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func confirmCommand(args ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	addConfirmFlags(cmd)
+	cmd.Flags().Parse(args)
+	return cmd
+}
+
+func TestConfirmChanges(t *testing.T) {
+	table := tables.New([]tables.Column{tables.NewColumn("name", "Name", true)}).
+		WithRows([]tables.Row{{"name": "/ecs/api"}})
+
+	assert.False(t, confirmChanges(context.Background(), confirmCommand("--dry-run"), table, "Apply?"))
+	assert.True(t, confirmChanges(context.Background(), confirmCommand("--yes"), table, "Apply?"))
+	assert.True(t, confirmChanges(context.Background(), confirmCommand("-y"), table, "Apply?"))
+}
+
+func TestApplyToGroups(t *testing.T) {
+	applied := []string{}
+	applyToGroups(context.Background(), []string{"/a", "/b"}, "Test", func(name string) error {
+		applied = append(applied, name)
+		return nil
+	})
+	assert.Equal(t, []string{"/a", "/b"}, applied)
+}
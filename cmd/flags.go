@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+// Read a flag holding an absolute or relative moment in time as a unix
+// timestamp in milliseconds
+func getTimestampFlag(cmd *cobra.Command, name string, now time.Time) int64 {
+	value, err := cmd.Flags().GetString(name)
+	utils.CheckErr(err)
+	if value == "" {
+		return 0
+	}
+
+	timestamp, err := utils.ParseTimestamp(value, now)
+	if err != nil {
+		utils.CheckErr(fmt.Errorf("Could not parse '%s' timestamp", name))
+	}
+	return timestamp
+}
+
+// Register flags used to select log streams within log groups
+func addStreamFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("stream", []string{}, "name of a log stream to fetch events from, can be repeated")
+	cmd.Flags().String("stream-prefix", "", "prefix filter on log stream name")
+	cmd.Flags().Bool("show-stream", false, "show log stream name of each event")
+
+	cmd.MarkFlagsMutuallyExclusive("stream", "stream-prefix")
+}
+
+// Read the log streams selected with the flags registered by addStreamFlags
+func getStreamFlags(cmd *cobra.Command) (names []string, prefix *string) {
+	streamNames, err := cmd.Flags().GetStringSlice("stream")
+	utils.CheckErr(err)
+	if len(streamNames) > 0 {
+		names = streamNames
+	}
+
+	streamPrefix, err := cmd.Flags().GetString("stream-prefix")
+	utils.CheckErr(err)
+	if streamPrefix != "" {
+		prefix = &streamPrefix
+	}
+
+	return names, prefix
+}
+
+// Register flags used to choose how log events are rendered
+func addRenderFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("json", false, "detect JSON messages and colour their keys and values")
+	cmd.Flags().Bool("pretty", false, "pretty print JSON messages")
+	cmd.Flags().StringSlice("fields", []string{}, "show only given fields of JSON messages, nested fields can be selected with dots")
+	addEventFlags(cmd)
+
+	cmd.MarkFlagsMutuallyExclusive("pretty", "fields")
+}
+
+// Register flags used to join and filter events before they are rendered
+func addEventFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("multiline", false, "join continuation lines, such as stack trace frames, to the previous event of their stream")
+	cmd.Flags().String("multiline-pattern", tlog.DefaultContinuationPattern.String(), "regular expression matching continuation lines")
+	cmd.Flags().Duration("multiline-window", tlog.DEFAULT_JOIN_WINDOW, "maximum time between joined lines")
+	cmd.Flags().String("level", "", "show only events of given level or above, among trace, debug, info, warn, error and fatal")
+}
+
+// Setup a log renderer using the flags registered by addRenderFlags and
+// addStreamFlags
+func newLogRenderer(cmd *cobra.Command) tlog.Renderer {
+	return newLevelFilter(cmd, newFormatRenderer(cmd))
+}
+
+// Wrap the renderer with a filter of events below the level given with the
+// flags registered by addEventFlags, if any
+func newLevelFilter(cmd *cobra.Command, r tlog.Renderer) tlog.Renderer {
+	level := getLevelFlag(cmd)
+	if level == tlog.LevelUnknown {
+		return r
+	}
+	return &tlog.LevelFilter{Min: level, Next: r}
+}
+
+func newFormatRenderer(cmd *cobra.Command) tlog.Renderer {
+	if outputFormat != tlog.FormatText {
+		r, err := tlog.NewFormatRenderer(outputFormat, os.Stdout)
+		utils.CheckErr(err)
+		return r
+	}
+
+	r := tlog.DefaultRenderer()
+
+	showStream, err := cmd.Flags().GetBool("show-stream")
+	utils.CheckErr(err)
+	r.ShowStream = showStream
+
+	json, err := cmd.Flags().GetBool("json")
+	utils.CheckErr(err)
+	pretty, err := cmd.Flags().GetBool("pretty")
+	utils.CheckErr(err)
+	fields, err := cmd.Flags().GetStringSlice("fields")
+	utils.CheckErr(err)
+
+	if json || pretty || len(fields) > 0 {
+		formatter := tlog.DefaultJsonFormatter()
+		formatter.Pretty = pretty
+		formatter.Fields = fields
+		r.Json = &formatter
+	}
+
+	return &r
+}
+
+// Wrap the renderer with a joiner of multi-line events if enabled with the
+// flags registered by addEventFlags, events are held back until flushed
+func newJoiner(cmd *cobra.Command, r tlog.Renderer) tlog.Renderer {
+	multiline, err := cmd.Flags().GetBool("multiline")
+	utils.CheckErr(err)
+	if !multiline {
+		return r
+	}
+
+	pattern, err := cmd.Flags().GetString("multiline-pattern")
+	utils.CheckErr(err)
+
+	j := tlog.NewJoiner(r)
+	j.Pattern, err = regexp.Compile(pattern)
+	if err != nil {
+		utils.CheckErr(fmt.Errorf("invalid multiline pattern: %w", err))
+	}
+	j.Window = getJoinWindow(cmd)
+	return j
+}
+
+// Maximum time between joined lines registered by addEventFlags
+func getJoinWindow(cmd *cobra.Command) time.Duration {
+	window, err := cmd.Flags().GetDuration("multiline-window")
+	utils.CheckErr(err)
+	return window
+}
+
+// Read the minimum level registered by addEventFlags, unknown if not given
+func getLevelFlag(cmd *cobra.Command) tlog.Level {
+	value, err := cmd.Flags().GetString("level")
+	utils.CheckErr(err)
+	if value == "" {
+		return tlog.LevelUnknown
+	}
+
+	level, err := tlog.ParseLevel(value)
+	utils.CheckErr(err)
+	return level
+}
+
+// Filter pattern to request events with, the level filter pattern is used if
+// a minimum level is given without a filter, so that fewer events are fetched.
+// Continuation lines have no level, so the level is only filtered on the
+// client when lines are joined
+func getFilterPattern(cmd *cobra.Command, filter string) string {
+	level := getLevelFlag(cmd)
+	if filter != "" || level == tlog.LevelUnknown {
+		return filter
+	}
+	multiline, err := cmd.Flags().GetBool("multiline")
+	utils.CheckErr(err)
+	if multiline {
+		return filter
+	}
+	return tlog.LevelFilterPattern(level)
+}
+
+// Print the table in the output format given with the global flags
+func printTable(table tables.Table) {
+	if allColumns {
+		table = table.WithAllColumns()
+	}
+
+	s, err := table.RenderFormat(outputFormat)
+	utils.CheckErr(err)
+	fmt.Println(s)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func eventCommand(args ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	addEventFlags(cmd)
+	cmd.Flags().Parse(args)
+	return cmd
+}
+
+func TestGetFilterPattern(t *testing.T) {
+	assert.Equal(t, "", getFilterPattern(eventCommand(), ""))
+	assert.Equal(t, tlog.LevelFilterPattern(tlog.LevelWarn), getFilterPattern(eventCommand("--level", "warn"), ""))
+	assert.Equal(t, "timeout", getFilterPattern(eventCommand("--level", "warn"), "timeout"))
+	// Continuation lines would be dropped by the level pattern
+	assert.Equal(t, "", getFilterPattern(eventCommand("--level", "warn", "--multiline"), ""))
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+// Register flags used to show events around the matching ones
+func addContextFlags(cmd *cobra.Command) {
+	cmd.Flags().IntP("after-context", "A", 0, "show given number of events of the same stream after each matching event")
+	cmd.Flags().IntP("before-context", "B", 0, "show given number of events of the same stream before each matching event")
+	cmd.Flags().IntP("context", "C", 0, "show given number of events of the same stream before and after each matching event")
+	cmd.Flags().Int("max-context", 100, "maximum number of matching events to fetch context of, later ones are shown without context")
+}
+
+// Wrap the renderer with a renderer of context events if requested with the
+// flags registered by addContextFlags
+func newContextRenderer(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client fetch.StreamEventsGetter,
+	r tlog.Renderer,
+) tlog.Renderer {
+	both, err := cmd.Flags().GetInt("context")
+	utils.CheckErr(err)
+	after, err := cmd.Flags().GetInt("after-context")
+	utils.CheckErr(err)
+	before, err := cmd.Flags().GetInt("before-context")
+	utils.CheckErr(err)
+	lookups, err := cmd.Flags().GetInt("max-context")
+	utils.CheckErr(err)
+
+	if !cmd.Flags().Changed("after-context") {
+		after = both
+	}
+	if !cmd.Flags().Changed("before-context") {
+		before = both
+	}
+	if after <= 0 && before <= 0 {
+		return r
+	}
+
+	return &contextRenderer{
+		ctx:     ctx,
+		client:  client,
+		before:  before,
+		after:   after,
+		lookups: lookups,
+		next:    r,
+		printed: map[string]map[string]bool{},
+	}
+}
+
+// Renderer of matching events along with the events of the same stream before
+// and after them, fetched with GetLogEvents.
+// Groups of events are separated as grep does, and a matching event which is
+// in the after context of the previous one continues its group.
+// Context is fetched for at most lookups matching events, since each one
+// takes up to two requests.
+type contextRenderer struct {
+	ctx     context.Context
+	client  fetch.StreamEventsGetter
+	before  int
+	after   int
+	lookups int
+	next    tlog.Renderer
+
+	groups  int
+	fetched int
+	// After context of the last matching event, rendered once the next
+	// event is known
+	pending       []tlog.Log
+	pendingStream string
+	// Events rendered in the last group of each stream
+	printed map[string]map[string]bool
+}
+
+func (c *contextRenderer) Render(log *tlog.Log) error {
+	stream := aws.ToString(log.GroupName) + "/" + aws.ToString(log.StreamName)
+	key := contextEventKey(log)
+
+	continues := false
+	if stream == c.pendingStream {
+		for i := range c.pending {
+			if contextEventKey(&c.pending[i]) == key {
+				c.pending = c.pending[:i]
+				continues = true
+				break
+			}
+		}
+	}
+	if err := c.renderPending(); err != nil {
+		return err
+	}
+
+	previous := c.printed[stream]
+	if !continues {
+		if c.groups > 0 {
+			if err := tlog.Separate(c.next); err != nil {
+				return err
+			}
+		}
+		c.printed[stream] = map[string]bool{}
+	}
+	c.groups++
+
+	// Events before one continuing the group were already rendered
+	before, after := c.before, c.after
+	if continues {
+		before = 0
+	}
+
+	var beforeEvents, afterEvents []types.OutputLogEvent
+	if c.fetched < c.lookups {
+		c.fetched++
+		var err error
+		beforeEvents, afterEvents, err = fetch.FetchContext(
+			c.ctx,
+			c.client,
+			aws.ToString(log.GroupName),
+			aws.ToString(log.StreamName),
+			aws.ToInt64(log.Timestamp),
+			aws.ToString(log.Message),
+			before,
+			after,
+		)
+		if err != nil {
+			// Show the matching event without context
+			style.PrintWarning("Could not fetch context of event: %s", utils.ErrorReason(err))
+		}
+	} else if c.fetched == c.lookups {
+		c.fetched++
+		style.PrintWarning("Context fetched for %d events, use --max-context to fetch more", c.lookups)
+	}
+
+	for _, event := range beforeEvents {
+		contextLog := utils.LogFromOutputEvent(log.GroupName, log.StreamName, &event)
+		if previous[contextEventKey(&contextLog)] {
+			continue
+		}
+		contextLog.Context = true
+		if err := c.render(stream, &contextLog); err != nil {
+			return err
+		}
+	}
+
+	if err := c.render(stream, log); err != nil {
+		return err
+	}
+
+	c.pending = []tlog.Log{}
+	c.pendingStream = stream
+	for _, event := range afterEvents {
+		contextLog := utils.LogFromOutputEvent(log.GroupName, log.StreamName, &event)
+		contextLog.Context = true
+		c.pending = append(c.pending, contextLog)
+	}
+	return nil
+}
+
+func (c *contextRenderer) Flush() error {
+	if err := c.renderPending(); err != nil {
+		return err
+	}
+	return tlog.Flush(c.next)
+}
+
+func (c *contextRenderer) renderPending() error {
+	for i := range c.pending {
+		if err := c.render(c.pendingStream, &c.pending[i]); err != nil {
+			return err
+		}
+	}
+	c.pending = nil
+	return nil
+}
+
+func (c *contextRenderer) render(stream string, log *tlog.Log) error {
+	c.printed[stream][contextEventKey(log)] = true
+	return c.next.Render(log)
+}
+
+// Identity of an event within its stream, context events have no id
+func contextEventKey(log *tlog.Log) string {
+	return strconv.FormatInt(aws.ToInt64(log.Timestamp), 10) + ":" + aws.ToString(log.Message)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addConfirmFlags(logsCreateCommand)
+
+	logsCreateCommand.Flags().String("retention", "", "number of days events are retained, never expire if not given")
+	logsCreateCommand.Flags().StringArrayP("tag", "t", []string{}, "tag of the log groups as key=value, can be repeated")
+	logsCreateCommand.Flags().String("class", "", "log group class, standard or infrequent-access")
+	logsCreateCommand.Flags().String("kms-key", "", "ARN of the KMS key used to encrypt events")
+}
+
+var logsCreateCommand = &cobra.Command{
+	Use:   "create group...",
+	Short: "Create cloudwatch log groups",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		retention, err := cmd.Flags().GetString("retention")
+		utils.CheckErr(err)
+		var days *int32
+		if retention != "" {
+			d, err := parseRetention(retention)
+			utils.CheckErr(err)
+			days = &d
+		}
+
+		pairs, err := cmd.Flags().GetStringArray("tag")
+		utils.CheckErr(err)
+		tags, err := parseTags(pairs)
+		utils.CheckErr(err)
+
+		class, err := cmd.Flags().GetString("class")
+		utils.CheckErr(err)
+		groupClass := types.LogGroupClass(strings.ReplaceAll(strings.ToUpper(class), "-", "_"))
+		if class != "" && groupClass != types.LogGroupClassStandard && groupClass != types.LogGroupClassInfrequentAccess {
+			utils.CheckErr(fmt.Errorf("invalid class '%s', expected standard or infrequent-access", class))
+		}
+
+		kmsKey, err := cmd.Flags().GetString("kms-key")
+		utils.CheckErr(err)
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		// Setup table
+		var (
+			keyIndex     = "index"
+			keyName      = "name"
+			keyRetention = "retention"
+			keyClass     = "class"
+			keyTags      = "tags"
+		)
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyName, "Name", true),
+			tables.NewColumn(keyRetention, "Retention", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyClass, "Class", true),
+			tables.NewColumn(keyTags, "Tags", true),
+		}
+
+		displayClass := string(groupClass)
+		if class == "" {
+			displayClass = "-"
+		}
+
+		rows := []tables.Row{}
+		for index, name := range args {
+			rows = append(rows, tables.Row{
+				keyIndex:     fmt.Sprintf("%d", index+1),
+				keyName:      name,
+				keyRetention: formatRetention(days),
+				keyClass:     displayClass,
+				keyTags:      formatTags(tags),
+			})
+		}
+
+		prompt := fmt.Sprintf("Create %d groups?", len(args))
+		if !confirmChanges(ctx, cmd, tables.New(columns).WithRows(rows), prompt) {
+			return
+		}
+
+		applyToGroups(ctx, args, "Create", func(name string) error {
+			params := &cloudwatchlogs.CreateLogGroupInput{
+				LogGroupName:  &name,
+				LogGroupClass: groupClass,
+			}
+			if len(tags) > 0 {
+				params.Tags = tags
+			}
+			if kmsKey != "" {
+				params.KmsKeyId = &kmsKey
+			}
+			_, err := client.CreateLogGroup(ctx, params)
+			if err != nil || days == nil {
+				return err
+			}
+
+			_, err = client.PutRetentionPolicy(ctx, &cloudwatchlogs.PutRetentionPolicyInput{
+				LogGroupName:    &name,
+				RetentionInDays: days,
+			})
+			return err
+		})
+	},
+}
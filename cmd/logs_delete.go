@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addGroupSelectionFlags(logsDeleteCommand)
+	addConfirmFlags(logsDeleteCommand)
+}
+
+var logsDeleteCommand = &cobra.Command{
+	Use:   "delete [group...]",
+	Short: "Delete cloudwatch log groups along with all their events",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroupsToChange(ctx, cmd, client, args)
+		if len(logGroups) == 0 {
+			style.PrintInfo("No groups found")
+			return
+		}
+
+		// Setup table
+		var (
+			keyIndex        = "index"
+			keyCreationDate = "creation"
+			keyName         = "name"
+			keyRetention    = "retention"
+			keyStored       = "stored"
+		)
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyCreationDate, "Creation", true),
+			tables.NewColumn(keyName, "Name", true),
+			tables.NewColumn(keyRetention, "Retention", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyStored, "Stored", true).WithAlignment(tables.Right),
+		}
+
+		rows := []tables.Row{}
+		names := []string{}
+		for index, group := range logGroups {
+			rows = append(rows, tables.Row{
+				keyIndex:        fmt.Sprintf("%d", index+1),
+				keyCreationDate: utils.FormatTimestamp(group.CreationTime, time.DateOnly),
+				keyName:         *group.LogGroupName,
+				keyRetention:    formatRetention(group.RetentionInDays),
+				keyStored:       formatStoredBytes(group.StoredBytes),
+			})
+			names = append(names, *group.LogGroupName)
+		}
+
+		prompt := fmt.Sprintf("Delete %d groups and all their events? This cannot be undone.", len(names))
+		if !confirmChanges(ctx, cmd, tables.New(columns).WithRows(rows), prompt) {
+			return
+		}
+
+		applyToGroups(ctx, names, "Delete", func(name string) error {
+			_, err := client.DeleteLogGroup(ctx, &cloudwatchlogs.DeleteLogGroupInput{
+				LogGroupName: &name,
+			})
+			return err
+		})
+	},
+}
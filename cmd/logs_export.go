@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/archive"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addGroupSelectionFlags(logsExportCommand)
+
+	logsExportCommand.Flags().StringP("dir", "d", ".", "directory to write exported files to")
+	logsExportCommand.Flags().Bool("per-hour", false, "write a file for each hour of events")
+	logsExportCommand.Flags().Bool("resume", false, "resume interrupted exports from their checkpoints")
+
+	logsExportCommand.Flags().StringP("filter", "f", "", "pattern filter on log events")
+	logsExportCommand.Flags().String("since", "1d", "moment in time to start the export, can be absolute or relative")
+	logsExportCommand.Flags().String("until", "0s", "moment in time to end the export, can be absolute or relative")
+
+	addStreamFlags(logsExportCommand)
+}
+
+var logsExportCommand = &cobra.Command{
+	Use:   "export [group...]",
+	Short: "Export log events of log groups to gzip compressed NDJSON files",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		now := time.Now()
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroups(ctx, cmd, client, args)
+
+		if len(logGroups) == 0 {
+			style.PrintInfo("No groups found")
+			return
+		}
+
+		dir, err := cmd.Flags().GetString("dir")
+		utils.CheckErr(err)
+		perHour, err := cmd.Flags().GetBool("per-hour")
+		utils.CheckErr(err)
+		resume, err := cmd.Flags().GetBool("resume")
+		utils.CheckErr(err)
+		filter, err := cmd.Flags().GetString("filter")
+		utils.CheckErr(err)
+
+		sinceUnix := getTimestampFlag(cmd, "since", now)
+		untilUnix := getTimestampFlag(cmd, "until", now)
+
+		streamNames, streamPrefix := getStreamFlags(cmd)
+
+		failed := 0
+		for _, group := range logGroups {
+			path := archive.CheckpointPath(dir, *group.LogGroupName)
+
+			// Export parameters of a resumed export are the ones it was
+			// started with
+			var checkpoint *archive.Checkpoint
+			if resume {
+				checkpoint, err = archive.LoadCheckpoint(path)
+				utils.CheckErr(err)
+			}
+			switch {
+			case checkpoint == nil:
+				checkpoint = &archive.Checkpoint{
+					Group:        *group.LogGroupName,
+					Since:        sinceUnix,
+					Until:        untilUnix,
+					Filter:       filter,
+					StreamNames:  streamNames,
+					StreamPrefix: streamPrefix,
+					PerHour:      perHour,
+					Files:        map[string]int64{},
+				}
+			case checkpoint.Complete:
+				style.PrintInfo("%s: already exported", *group.LogGroupName)
+				continue
+			default:
+				style.PrintInfo("%s: resuming after %d events", *group.LogGroupName, checkpoint.Events)
+			}
+
+			err = exportLogGroup(ctx, client, dir, checkpoint, path)
+			fmt.Fprintln(os.Stderr)
+			exitIfDone(ctx)
+			if err != nil {
+				style.PrintError("%s: %s", *group.LogGroupName, utils.ErrorReason(err))
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			style.PrintError(
+				"Export is partial, %d out of %d groups failed, run again with --resume to continue",
+				failed,
+				len(logGroups),
+			)
+			os.Exit(utils.EXIT_PARTIAL)
+		}
+	},
+}
+
+// Export the events of the group of the checkpoint, saving the checkpoint
+// after each page of events
+func exportLogGroup(
+	ctx context.Context,
+	client *cloudwatchlogs.Client,
+	dir string,
+	checkpoint *archive.Checkpoint,
+	path string,
+) error {
+	w, err := archive.NewWriter(dir, checkpoint.Group, checkpoint.PerHour, checkpoint.Files)
+	if err != nil {
+		return err
+	}
+	// Files are left open by a failure before the next commit
+	defer w.Close()
+
+	newFetcher := func(since int64) fetch.LogsFetcher {
+		return fetch.NewLogsFetcher(
+			ctx,
+			&fetch.LogsFetcherClient{
+				Client: client,
+				Params: cloudwatchlogs.FilterLogEventsInput{
+					LogGroupName:        &checkpoint.Group,
+					StartTime:           &since,
+					EndTime:             &checkpoint.Until,
+					FilterPattern:       &checkpoint.Filter,
+					LogStreamNames:      checkpoint.StreamNames,
+					LogStreamNamePrefix: checkpoint.StreamPrefix,
+				},
+			},
+		)
+	}
+
+	logFetcher := newFetcher(checkpoint.Since).WithNextToken(checkpoint.NextToken)
+	resumed := checkpoint.NextToken != nil
+	fromTimestamp := false
+
+	for logFetcher.HasNextPage() {
+		events, err := logFetcher.NextPage()
+
+		// Tokens expire after a day, continue from the timestamp of the
+		// last exported event instead
+		if resumed && isExpiredToken(err) {
+			style.PrintWarning("%s: checkpoint token expired, resuming from the last exported event", checkpoint.Group)
+			logFetcher = newFetcher(max(checkpoint.Since, checkpoint.Timestamp))
+			resumed = false
+			fromTimestamp = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		resumed = false
+
+		for _, event := range events {
+			timestamp, id := aws.ToInt64(event.Timestamp), aws.ToString(event.EventId)
+			if fromTimestamp && checkpoint.Exported(timestamp, id) {
+				continue
+			}
+			log := utils.LogFromCloudwatchEvent(&checkpoint.Group, &event)
+			if err := w.Write(tlog.NewRecord(&log), timestamp); err != nil {
+				return err
+			}
+			checkpoint.Add(timestamp, id)
+		}
+
+		if err := w.Commit(); err != nil {
+			return err
+		}
+		checkpoint.NextToken = logFetcher.NextToken()
+		if err := checkpoint.Save(path); err != nil {
+			return err
+		}
+		style.PrintProgress("%s: %d events exported", checkpoint.Group, checkpoint.Events)
+	}
+
+	checkpoint.Complete = true
+	return checkpoint.Save(path)
+}
+
+// Whether err is caused by an expired pagination token, other invalid
+// parameters are reported as errors
+func isExpiredToken(err error) bool {
+	var invalid *types.InvalidParameterException
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	message := strings.ToLower(invalid.ErrorMessage())
+	return strings.Contains(message, "token") && strings.Contains(message, "expired")
+}
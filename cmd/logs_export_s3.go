@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/ravvio/awst/archive"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	logsExportS3Command.Flags().StringP("bucket", "b", "", "destination bucket of the export")
+	logsExportS3Command.Flags().String("prefix", "exportedlogs", "prefix of the exported objects in the bucket")
+	logsExportS3Command.Flags().String("stream-prefix", "", "export only log streams starting with prefix")
+
+	logsExportS3Command.Flags().String("since", "1d", "moment in time to start the export, can be absolute or relative")
+	logsExportS3Command.Flags().String("until", "0s", "moment in time to end the export, can be absolute or relative")
+
+	logsExportS3Command.MarkFlagRequired("bucket")
+}
+
+var logsExportS3Command = &cobra.Command{
+	Use:   "export-s3 group...",
+	Short: "Export log events of log groups to a S3 bucket with export tasks, one group after the other",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		now := time.Now()
+
+		bucket, err := cmd.Flags().GetString("bucket")
+		utils.CheckErr(err)
+		prefix, err := cmd.Flags().GetString("prefix")
+		utils.CheckErr(err)
+		streamPrefix, err := cmd.Flags().GetString("stream-prefix")
+		utils.CheckErr(err)
+
+		sinceUnix := getTimestampFlag(cmd, "since", now)
+		untilUnix := getTimestampFlag(cmd, "until", now)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+		s3client := s3.NewFromConfig(cfg)
+
+		failed := 0
+		for _, group := range args {
+			params := cloudwatchlogs.CreateExportTaskInput{
+				LogGroupName:      &group,
+				Destination:       &bucket,
+				DestinationPrefix: &prefix,
+				From:              &sinceUnix,
+				To:                &untilUnix,
+				TaskName:          aws.String(fmt.Sprintf("awst-%s-%d", archive.BaseName(group), now.Unix())),
+			}
+			if streamPrefix != "" {
+				params.LogStreamNamePrefix = &streamPrefix
+			}
+
+			// Only one export task can be active at a time, so groups are
+			// exported one after the other
+			start := time.Now()
+			task := fetch.NewExportTask(client, params)
+			task.OnQueued = func(delay time.Duration) {
+				style.PrintProgress("%s: waiting for another export task, retrying in %s", group, delay.Round(time.Second))
+			}
+			task.OnProgress = func(task *types.ExportTask) {
+				var status types.ExportTaskStatusCode
+				if task.Status != nil {
+					status = task.Status.Code
+				}
+				style.PrintProgress("%s: %s, %s elapsed", group, status, time.Since(start).Round(time.Second))
+			}
+
+			result, err := task.Run(ctx)
+			fmt.Fprintln(os.Stderr)
+			exitIfDone(ctx)
+			if err != nil {
+				style.PrintError("%s: %s", group, utils.ErrorReason(err))
+				failed++
+				continue
+			}
+
+			// Objects are written under a directory named after the task
+			objectsPrefix := path.Join(prefix, aws.ToString(result.TaskId)) + "/"
+			style.PrintInfo("%s: exported to s3://%s/%s", group, bucket, objectsPrefix)
+			printObjects(ctx, s3client, bucket, objectsPrefix, -1)
+		}
+
+		if failed > 0 {
+			style.PrintError("Export is partial, %d out of %d groups failed", failed, len(args))
+			os.Exit(utils.EXIT_PARTIAL)
+		}
+	},
+}
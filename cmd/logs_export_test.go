@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsExpiredToken(t *testing.T) {
+	expired := &types.InvalidParameterException{Message: aws.String("The specified nextToken has expired.")}
+	assert.True(t, isExpiredToken(expired))
+	assert.True(t, isExpiredToken(fmt.Errorf("operation error: %w", expired)))
+
+	invalid := &types.InvalidParameterException{Message: aws.String("Invalid filter pattern")}
+	assert.False(t, isExpiredToken(invalid))
+	assert.False(t, isExpiredToken(errors.New("token expired")))
+	assert.False(t, isExpiredToken(nil))
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+// Message used to check the syntax of filter patterns
+const patternCheckMessage = "awst"
+
+// Columns of the table of events filter patterns are tested on
+const (
+	patternKeyIndex     = "index"
+	patternKeyGroup     = "group"
+	patternKeyTimestamp = "timestamp"
+	patternKeyMatch     = "match"
+	patternKeyValue     = "value"
+	patternKeyExtracted = "extracted"
+	patternKeyMessage   = "message"
+)
+
+// Register flags used to select the events filter patterns are tested on
+func addPatternTestFlags(cmd *cobra.Command) {
+	cmd.Flags().String("since", "1h", "moment in time to start fetching events to test, can be absolute or relative")
+	cmd.Flags().String("until", "0s", "moment in time to end fetching events to test, can be absolute or relative")
+	cmd.Flags().Int32P("limit", "l", 1000, "limit number of log events to test from each group")
+	cmd.Flags().Bool("show-all", false, "show events which do not match too")
+}
+
+// Filter pattern tested on the events of a group, with the metric value
+// emitted by matching events if it is a metric filter
+type groupPattern struct {
+	pattern     string
+	metricValue *string
+}
+
+// Test filter patterns on events of each group fetched with the flags
+// registered by addPatternTestFlags, and print the matching events. Groups
+// without a pattern are skipped.
+func testGroupPatterns(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client *cloudwatchlogs.Client,
+	logGroups []types.LogGroup,
+	patterns map[string]groupPattern,
+) {
+	now := time.Now()
+	sinceUnix := getTimestampFlag(cmd, "since", now)
+	untilUnix := getTimestampFlag(cmd, "until", now)
+	limit, err := cmd.Flags().GetInt32("limit")
+	utils.CheckErr(err)
+	showAll, err := cmd.Flags().GetBool("show-all")
+	utils.CheckErr(err)
+
+	// Setup table
+	showValue := false
+	for _, p := range patterns {
+		showValue = showValue || p.metricValue != nil
+	}
+
+	columns := []tables.Column{
+		tables.NewColumn(patternKeyIndex, "#", true).WithAlignment(tables.Right),
+		tables.NewColumn(patternKeyGroup, "Group", len(patterns) > 1),
+		tables.NewColumn(patternKeyTimestamp, "Timestamp", true),
+		tables.NewColumn(patternKeyMatch, "Match", showAll),
+		tables.NewColumn(patternKeyValue, "Value", showValue).WithAlignment(tables.Right),
+		tables.NewColumn(patternKeyExtracted, "Extracted", false),
+		tables.NewColumn(patternKeyMessage, "Message", true),
+	}
+
+	rows := []tables.Row{}
+	for _, group := range logGroups {
+		p, ok := patterns[*group.LogGroupName]
+		if !ok {
+			continue
+		}
+
+		style.PrintProgress("Testing %s", *group.LogGroupName)
+		logsFetcher := fetch.NewLogsFetcher(
+			ctx,
+			&fetch.LogsFetcherClient{
+				Client: client,
+				Params: cloudwatchlogs.FilterLogEventsInput{
+					LogGroupName: group.LogGroupName,
+					StartTime:    &sinceUnix,
+					EndTime:      &untilUnix,
+				},
+			},
+		).WithLimit(limit)
+		events, err := logsFetcher.All()
+
+		// Events fetched before a failure are still tested
+		var truncated *fetch.TruncatedError
+		if err != nil && !errors.As(err, &truncated) {
+			fmt.Fprintln(os.Stderr)
+			checkCtxErr(ctx, err)
+		}
+
+		messages := []string{}
+		for _, event := range events {
+			messages = append(messages, aws.ToString(event.Message))
+		}
+		matches, err := fetch.MatchFilterPattern(ctx, client, p.pattern, messages)
+		if err != nil {
+			fmt.Fprintln(os.Stderr)
+			exitIfDone(ctx)
+			utils.CheckErr(fmt.Errorf("invalid filter pattern '%s': %s", p.pattern, utils.ErrorReason(err)))
+		}
+
+		fmt.Fprintln(os.Stderr)
+		style.PrintInfo("%s: %d out of %d events match", *group.LogGroupName, len(matches), len(events))
+		if truncated != nil {
+			style.PrintWarning("%s: only %d events tested, fetching failed: %s", *group.LogGroupName, truncated.Fetched, utils.ErrorReason(truncated.Err))
+		}
+
+		rows = append(rows, patternTestRows(*group.LogGroupName, p, events, matches, showAll, len(rows))...)
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+	printTable(tables.New(columns).WithRows(rows))
+}
+
+// Rows of the events of a group a pattern was tested on, with the matches
+// returned by MatchFilterPattern. Events which do not match are skipped
+// unless showAll is set, rows are numbered after the given offset.
+func patternTestRows(
+	group string,
+	p groupPattern,
+	events []types.FilteredLogEvent,
+	matches map[int]map[string]string,
+	showAll bool,
+	offset int,
+) []tables.Row {
+	rows := []tables.Row{}
+	for i, event := range events {
+		extracted, match := matches[i]
+		if !match && !showAll {
+			continue
+		}
+
+		row := tables.Row{
+			patternKeyIndex:     fmt.Sprintf("%d", offset+len(rows)+1),
+			patternKeyGroup:     group,
+			patternKeyTimestamp: utils.FormatTimestamp(event.Timestamp, time.DateTime),
+			patternKeyMatch:     strconv.FormatBool(match),
+			patternKeyExtracted: formatTags(extracted),
+			patternKeyMessage:   strings.TrimSpace(aws.ToString(event.Message)),
+		}
+		if match && p.metricValue != nil {
+			row[patternKeyValue] = metricValue(*p.metricValue, extracted)
+		}
+		if outputFormat == tables.FormatText {
+			row[patternKeyMessage] = truncateText(row[patternKeyMessage], patternTextWidth)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Value emitted by a metric filter for an event, the metric value is either
+// a constant or a field extracted from the event such as $.latency. Fields
+// the event does not have emit no value.
+func metricValue(value string, extracted map[string]string) string {
+	if !strings.HasPrefix(value, "$") {
+		return value
+	}
+	if v, ok := extracted[value]; ok {
+		return v
+	}
+	return "-"
+}
+
+// Check the syntax of a filter pattern
+func checkFilterPattern(ctx context.Context, client *cloudwatchlogs.Client, pattern string) {
+	_, err := fetch.MatchFilterPattern(ctx, client, pattern, []string{patternCheckMessage})
+	if err != nil {
+		exitIfDone(ctx)
+		utils.CheckErr(fmt.Errorf("invalid filter pattern '%s': %s", pattern, utils.ErrorReason(err)))
+	}
+}
+
+// Fetch the metric filters of each log group by name, with names starting
+// with the given prefix if not empty
+func fetchMetricFilters(
+	ctx context.Context,
+	client *cloudwatchlogs.Client,
+	logGroups []types.LogGroup,
+	namePrefix string,
+) map[string][]types.MetricFilter {
+	filters := map[string][]types.MetricFilter{}
+	for _, group := range logGroups {
+		params := cloudwatchlogs.DescribeMetricFiltersInput{
+			LogGroupName: group.LogGroupName,
+		}
+		if namePrefix != "" {
+			params.FilterNamePrefix = &namePrefix
+		}
+
+		filtersFetcher := fetch.NewMetricFiltersFetcher(
+			ctx,
+			&fetch.MetricFiltersFetcherClient{
+				Client: client,
+				Params: params,
+			},
+		)
+		groupFilters, err := filtersFetcher.All()
+		checkCtxErr(ctx, err)
+		filters[*group.LogGroupName] = groupFilters
+	}
+	return filters
+}
+
+// Fetch the subscription filters of each log group by name, with names
+// starting with the given prefix if not empty
+func fetchSubscriptionFilters(
+	ctx context.Context,
+	client *cloudwatchlogs.Client,
+	logGroups []types.LogGroup,
+	namePrefix string,
+) map[string][]types.SubscriptionFilter {
+	filters := map[string][]types.SubscriptionFilter{}
+	for _, group := range logGroups {
+		params := cloudwatchlogs.DescribeSubscriptionFiltersInput{
+			LogGroupName: group.LogGroupName,
+		}
+		if namePrefix != "" {
+			params.FilterNamePrefix = &namePrefix
+		}
+
+		filtersFetcher := fetch.NewSubscriptionFiltersFetcher(
+			ctx,
+			&fetch.SubscriptionFiltersFetcherClient{
+				Client: client,
+				Params: params,
+			},
+		)
+		groupFilters, err := filtersFetcher.All()
+		checkCtxErr(ctx, err)
+		filters[*group.LogGroupName] = groupFilters
+	}
+	return filters
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricValue(t *testing.T) {
+	extracted := map[string]string{"$.latency": "120"}
+	assert.Equal(t, "120", metricValue("$.latency", extracted))
+	assert.Equal(t, "1", metricValue("1", extracted))
+	// Events without the field emit no value
+	assert.Equal(t, "-", metricValue("$.size", extracted))
+	assert.Equal(t, "-", metricValue("$.latency", map[string]string{}))
+}
+
+func TestPatternTestRows(t *testing.T) {
+	events := []types.FilteredLogEvent{
+		{Timestamp: aws.Int64(0), Message: aws.String(`{"latency":120}`)},
+		{Timestamp: aws.Int64(0), Message: aws.String("no match")},
+		{Timestamp: aws.Int64(0), Message: aws.String(`{"status":500}`)},
+	}
+	matches := map[int]map[string]string{
+		0: {"$.latency": "120"},
+		2: {},
+	}
+	p := groupPattern{pattern: "{ $.status = * }", metricValue: aws.String("$.latency")}
+
+	rows := patternTestRows("/ecs/api", p, events, matches, false, 3)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "4", rows[0][patternKeyIndex])
+	assert.Equal(t, "120", rows[0][patternKeyValue])
+	assert.Equal(t, "5", rows[1][patternKeyIndex])
+	assert.Equal(t, "-", rows[1][patternKeyValue])
+	assert.Equal(t, `{"status":500}`, rows[1][patternKeyMessage])
+
+	// Events which do not match are shown without a value
+	rows = patternTestRows("/ecs/api", p, events, matches, true, 0)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, "false", rows[1][patternKeyMatch])
+	assert.Equal(t, "no match", rows[1][patternKeyMessage])
+	_, ok := rows[1][patternKeyValue]
+	assert.False(t, ok)
+	assert.Equal(t, "true", rows[2][patternKeyMatch])
+
+	// Subscription filters have no value
+	rows = patternTestRows("/ecs/api", groupPattern{pattern: "ERROR"}, events, matches, false, 0)
+	_, ok = rows[0][patternKeyValue]
+	assert.False(t, ok)
+}
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"os"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/ravvio/awst/fetch"
 	"github.com/ravvio/awst/ui/style"
 	"github.com/ravvio/awst/ui/tlog"
@@ -14,14 +16,24 @@ import (
 )
 
 func init() {
-	logsGetCommand.Flags().BoolP("all", "a", false, "do not limit of log events to fetch from each group")
-	logsGetCommand.Flags().Int32P("limit", "l", 10000, "limit number of log events to fetch from each group")
+	addLogsGetFlags(logsGetCommand)
+}
+
+func addLogsGetFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("all", "a", false, "do not limit of log events to fetch from each group")
+	cmd.Flags().Int32P("limit", "l", 10000, "limit number of log events to fetch from each group")
+
+	cmd.Flags().StringP("filter", "f", "", "pattern filter on log events")
+	cmd.Flags().String("since", "1d", "moment in time to start the search, can be absolute or relative")
+	cmd.Flags().String("until", "0s", "moment in time to end the search, can be absolute or relative")
+	cmd.Flags().Int("slices", 1, "split the time range in slices which are fetched concurrently")
+	cmd.Flags().Int("max-par", 5, "maximum parallelization for fetching time slices")
 
-	logsGetCommand.Flags().StringP("filter", "f", "", "pattern filter on log events")
-	logsGetCommand.Flags().String("since", "1d", "moment in time to start the search, can be absolute or relative")
-	logsGetCommand.Flags().String("until", "0s", "moment in time to end the search, can be absolute or relative")
+	addStreamFlags(cmd)
+	addRenderFlags(cmd)
+	addContextFlags(cmd)
 
-	logsGetCommand.Flags().BoolP("tail", "t", false, "start live tail")
+	cmd.Flags().BoolP("tail", "t", false, "start live tail")
 }
 
 var logsGetCommand = &cobra.Command{
@@ -29,86 +41,136 @@ var logsGetCommand = &cobra.Command{
 	Short: "Get cloudwatch logs of given log group",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
 		// Load config
-		cfg, err := loadAwsConfig(context.TODO())
+		cfg, err := loadAwsConfig(ctx)
 		utils.CheckErr(err)
 
-		now := time.Now()
-
-		logGroupName := args[0]
-
-		// Setup params
-		filter, err := cmd.Flags().GetString("filter")
-		utils.CheckErr(err)
-		limitEvents, err := cmd.Flags().GetInt32("limit")
-		utils.CheckErr(err)
-		allEvents, err := cmd.Flags().GetBool("all")
-		utils.CheckErr(err)
-		// tail, err := cmd.Flags().GetBool("tail")
-		// utils.CheckErr(err)
+		client := fetch.CloudwatchLiveTailClient{Client: cloudwatchlogs.NewFromConfig(cfg)}
+		truncated, err := getLogs(ctx, cmd, client, args[0], newLogRenderer(cmd))
+		checkCtxErr(ctx, err)
 
-		sinceDate, err := cmd.Flags().GetString("since")
-		var sinceUnix int64
-		utils.CheckErr(err)
-		if sinceDate != "" {
-			if t, err := utils.ParseDatetime(sinceDate); err == nil && t.UnixMilli() >= 0 {
-				sinceUnix = t.UnixMilli()
-			} else if d, err := utils.ParseDuration(sinceDate); err == nil {
-				sinceUnix = now.UnixMilli() - d
-			} else {
-				utils.CheckErr(fmt.Errorf("Could not parse 'since' timestamp"))
-			}
+		// Events fetched before the tail started are still partial
+		if truncated != nil {
+			os.Exit(utils.EXIT_PARTIAL)
 		}
+		exitIfDone(ctx)
+	},
+}
 
-		untilDate, err := cmd.Flags().GetString("until")
-		var untilUnix int64
-		utils.CheckErr(err)
-		if untilDate != "" {
-			if t, err := utils.ParseDatetime(untilDate); err == nil && t.UnixMilli() >= 0 {
-				untilUnix = t.UnixMilli()
-			} else if d, err := utils.ParseDuration(untilDate); err == nil {
-				untilUnix = now.UnixMilli() - d
-			} else {
-				utils.CheckErr(fmt.Errorf("Could not parse 'until' timestamp"))
-			}
-		}
+// Client of the requests of logs get, such as fetch.CloudwatchLiveTailClient
+type logsGetClient interface {
+	fetch.LiveTailClient
+	fetch.StreamEventsGetter
+	fetch.GroupsDescriber
+}
 
-		// Request
-		client := cloudwatchlogs.NewFromConfig(cfg)
-		logFetcher := fetch.NewLogsFetcher(
-			context.TODO(),
+// Fetch and render the events of the group, then live tail it with --tail
+// until the context is done. The error which truncated the fetched events is
+// returned, unless the context was done before the tail started.
+func getLogs(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client logsGetClient,
+	logGroupName string,
+	r tlog.Renderer,
+) (*fetch.TruncatedError, error) {
+	now := time.Now()
+
+	// Setup params
+	filter, err := cmd.Flags().GetString("filter")
+	utils.CheckErr(err)
+	filter = getFilterPattern(cmd, filter)
+	limitEvents, err := cmd.Flags().GetInt32("limit")
+	utils.CheckErr(err)
+	allEvents, err := cmd.Flags().GetBool("all")
+	utils.CheckErr(err)
+	tail, err := cmd.Flags().GetBool("tail")
+	utils.CheckErr(err)
+
+	sinceUnix := getTimestampFlag(cmd, "since", now)
+	untilUnix := getTimestampFlag(cmd, "until", now)
+
+	streamNames, streamPrefix := getStreamFlags(cmd)
+	slices, err := cmd.Flags().GetInt("slices")
+	utils.CheckErr(err)
+	maxPar, err := cmd.Flags().GetInt("max-par")
+	utils.CheckErr(err)
+
+	// Time slices are fetched concurrently and rendered one after the other
+	logFetchers := []fetch.LogsFetcher{}
+	for _, slice := range fetch.SplitTimeRange(sinceUnix, untilUnix, slices) {
+		logFetchers = append(logFetchers, fetch.NewLogsFetcher(
+			ctx,
 			&fetch.LogsFetcherClient{
 				Client: client,
 				Params: cloudwatchlogs.FilterLogEventsInput{
-					LogGroupName:  &logGroupName,
-					StartTime:     &sinceUnix,
-					EndTime:       &untilUnix,
-					FilterPattern: &filter,
+					LogGroupName:        &logGroupName,
+					StartTime:           &slice.Start,
+					EndTime:             &slice.End,
+					FilterPattern:       &filter,
+					LogStreamNames:      streamNames,
+					LogStreamNamePrefix: streamPrefix,
 				},
 			},
+		))
+	}
+	limit := int32(-1)
+	if !allEvents {
+		limit = limitEvents
+	}
+
+	// Render events as pages are received, until an interruption or a
+	// failed page
+	events := newContextRenderer(ctx, cmd, client, newJoiner(cmd, r))
+	rendered := 0
+	err = fetch.Concat(logFetchers, maxPar, limit, func(event types.FilteredLogEvent) error {
+		log := utils.LogFromCloudwatchEvent(&logGroupName, &event)
+		rendered++
+		return events.Render(&log)
+	})
+	if err := tlog.Flush(events); err != nil {
+		return nil, err
+	}
+	var truncated *fetch.TruncatedError
+	if ctx.Err() == nil && err != nil && !errors.As(err, &truncated) {
+		return nil, err
+	}
+
+	if rendered == 0 && ctx.Err() == nil {
+		style.PrintInfo("No events found")
+	}
+
+	// Interrupted before the tail
+	if ctx.Err() != nil {
+		return nil, nil
+	}
+
+	if truncated != nil {
+		style.PrintError(
+			"Results are partial, fetching stopped after %d events: %s",
+			truncated.Fetched,
+			utils.ErrorReason(truncated.Err),
 		)
-		if !allEvents {
-			logFetcher = logFetcher.WithLimit(limitEvents)
-		}
-
-		logEvents, err := logFetcher.All()
-		utils.CheckErr(err)
-
-		if len(logEvents) == 0 {
-			style.PrintInfo("No events found")
-			return
-		}
-
-		r := tlog.DefaultRenderer()
-		for _, event := range logEvents {
-			err = r.Render(&tlog.Log{
-				GroupName: &logGroupName,
-				Timestamp: event.Timestamp,
-				Message:   event.Message,
-			})
-			utils.CheckErr(err)
-		}
-
-		// TODO Tail
-	},
+	}
+
+	if !tail {
+		return truncated, nil
+	}
+
+	logGroup, err := describeLogGroup(ctx, client, logGroupName)
+	if err != nil {
+		return truncated, err
+	}
+
+	err = liveTail(
+		ctx,
+		client,
+		[]types.LogGroup{logGroup},
+		liveTailParams(filter, streamNames, streamPrefix),
+		newJoiner(cmd, r),
+		getJoinWindow(cmd),
+	)
+	return truncated, err
 }
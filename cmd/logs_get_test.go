@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSessionStream struct {
+	events chan types.StartLiveTailResponseStream
+	err    error
+}
+
+func (s *fakeSessionStream) Events() <-chan types.StartLiveTailResponseStream {
+	return s.events
+}
+
+func (s *fakeSessionStream) Close() error {
+	return nil
+}
+
+func (s *fakeSessionStream) Err() error {
+	return s.err
+}
+
+// Client of logs get serving the stored events by time range, live tail
+// sessions send the given events and then end with the given errors
+type fakeLogsGetClient struct {
+	mu sync.Mutex
+
+	events   []types.FilteredLogEvent
+	sessions [][]types.StartLiveTailResponseStream
+	errs     []error
+
+	filterParams []cloudwatchlogs.FilterLogEventsInput
+	tailParams   []cloudwatchlogs.StartLiveTailInput
+}
+
+func (c *fakeLogsGetClient) FilterLogEvents(
+	ctx context.Context,
+	params *cloudwatchlogs.FilterLogEventsInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.filterParams = append(c.filterParams, *params)
+
+	events := []types.FilteredLogEvent{}
+	for _, event := range c.events {
+		if *event.Timestamp < aws.ToInt64(params.StartTime) {
+			continue
+		}
+		if params.EndTime != nil && *event.Timestamp > *params.EndTime {
+			continue
+		}
+		events = append(events, event)
+	}
+	return &cloudwatchlogs.FilterLogEventsOutput{Events: events}, nil
+}
+
+func (c *fakeLogsGetClient) GetLogEvents(
+	ctx context.Context,
+	params *cloudwatchlogs.GetLogEventsInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	return &cloudwatchlogs.GetLogEventsOutput{}, nil
+}
+
+func (c *fakeLogsGetClient) DescribeLogGroups(
+	ctx context.Context,
+	params *cloudwatchlogs.DescribeLogGroupsInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	name := aws.ToString(params.LogGroupNamePrefix)
+	return &cloudwatchlogs.DescribeLogGroupsOutput{
+		LogGroups: []types.LogGroup{{
+			LogGroupName: &name,
+			LogGroupArn:  aws.String(logGroupArnPrefix + name),
+		}},
+	}, nil
+}
+
+func (c *fakeLogsGetClient) StartLiveTailStream(
+	ctx context.Context,
+	params *cloudwatchlogs.StartLiveTailInput,
+) (cloudwatchlogs.StartLiveTailResponseStreamReader, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tailParams = append(c.tailParams, *params)
+
+	session := c.sessions[0]
+	err := c.errs[0]
+	c.sessions = c.sessions[1:]
+	c.errs = c.errs[1:]
+
+	events := make(chan types.StartLiveTailResponseStream, len(session))
+	for _, e := range session {
+		events <- e
+	}
+	close(events)
+	return &fakeSessionStream{events: events, err: err}, nil
+}
+
+const logGroupArnPrefix = "arn:aws:logs:eu-west-1:123456789012:log-group:"
+
+func filteredEvent(message string, timestamp time.Time) types.FilteredLogEvent {
+	return types.FilteredLogEvent{
+		LogStreamName: aws.String("web"),
+		Timestamp:     aws.Int64(timestamp.UnixMilli()),
+		Message:       aws.String(message),
+	}
+}
+
+func TestLogsGetTail(t *testing.T) {
+	now := time.Now()
+	tailed := now.Add(time.Minute)
+	arn := logGroupArnPrefix + "/ecs/api"
+
+	client := &fakeLogsGetClient{
+		events: []types.FilteredLogEvent{
+			filteredEvent("old", now.Add(-time.Hour)),
+			filteredEvent("h1", now.Add(-2*time.Minute)),
+			filteredEvent("h2", now.Add(-time.Minute)),
+			// Ingested while the tail runs, t2 is missed by the closed
+			// session and fetched again on reconnect
+			filteredEvent("t1", tailed),
+			filteredEvent("t2", tailed),
+		},
+		sessions: [][]types.StartLiveTailResponseStream{
+			{
+				&types.StartLiveTailResponseStreamMemberSessionStart{},
+				&types.StartLiveTailResponseStreamMemberSessionUpdate{
+					Value: types.LiveTailSessionUpdate{
+						SessionResults: []types.LiveTailSessionLogEvent{{
+							LogGroupIdentifier: aws.String(arn),
+							LogStreamName:      aws.String("web"),
+							Timestamp:          aws.Int64(tailed.UnixMilli()),
+							Message:            aws.String("t1"),
+						}},
+					},
+				},
+			},
+			{
+				&types.StartLiveTailResponseStreamMemberSessionStart{},
+			},
+		},
+		errs: []error{
+			nil,
+			&types.AccessDeniedException{Message: aws.String("denied")},
+		},
+	}
+
+	cmd := &cobra.Command{Use: "get"}
+	addLogsGetFlags(cmd)
+	err := cmd.Flags().Parse([]string{"--tail", "--since", "10m", "--filter", "ERROR", "--stream", "web"})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	r, err := tlog.NewFormatRenderer(tlog.FormatRaw, &buf)
+	assert.NoError(t, err)
+
+	truncated, err := getLogs(context.Background(), cmd, client, "/ecs/api", r)
+	assert.Nil(t, truncated)
+	var accessDenied *types.AccessDeniedException
+	assert.ErrorAs(t, err, &accessDenied)
+
+	// Historical events are not printed again by the backfill and events
+	// received by the tail only once
+	assert.Equal(t, "h1\nh2\nt1\nt2\n", buf.String())
+
+	// Flags are passed to the historical fetch and the tail sessions
+	assert.Equal(t, "/ecs/api", aws.ToString(client.filterParams[0].LogGroupName))
+	assert.Equal(t, "ERROR", aws.ToString(client.filterParams[0].FilterPattern))
+	assert.Equal(t, []string{"web"}, client.filterParams[0].LogStreamNames)
+	assert.Len(t, client.tailParams, 2)
+	for _, params := range client.tailParams {
+		assert.Equal(t, []string{arn}, params.LogGroupIdentifiers)
+		assert.Equal(t, "ERROR", aws.ToString(params.LogEventFilterPattern))
+		assert.Equal(t, []string{"web"}, params.LogStreamNames)
+	}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+// Register flags used to select log groups by name prefix or pattern
+func addGroupSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("pattern", "e", "", "pattern filter on log group name")
+	cmd.Flags().StringP("prefix", "p", "", "prefix filter on log group name")
+	cmd.Flags().Bool("all-groups", false, "do not limit of log groups to use")
+	cmd.Flags().Int32("limit-groups", 50, "limit number of log groups to use")
+
+	cmd.MarkFlagsMutuallyExclusive("pattern", "prefix")
+}
+
+// Fetch the log groups with the given names or, if no name is given, the ones
+// selected by the flags registered with addGroupSelectionFlags
+func selectLogGroups(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client *cloudwatchlogs.Client,
+	names []string,
+) []types.LogGroup {
+	return fetchLogGroups(ctx, cmd, client, names, false)
+}
+
+// Fetch log groups like selectLogGroups for commands which change them, which
+// fail if more groups than the limit match instead of changing only some
+func selectLogGroupsToChange(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client *cloudwatchlogs.Client,
+	names []string,
+) []types.LogGroup {
+	return fetchLogGroups(ctx, cmd, client, names, true)
+}
+
+func fetchLogGroups(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client *cloudwatchlogs.Client,
+	names []string,
+	strict bool,
+) []types.LogGroup {
+	if len(names) > 0 {
+		logGroups := []types.LogGroup{}
+		for _, name := range names {
+			logGroup, err := describeLogGroup(ctx, client, name)
+			checkCtxErr(ctx, err)
+			logGroups = append(logGroups, logGroup)
+		}
+		return logGroups
+	}
+
+	// Setup params for descibe operation
+	describeParams := &cloudwatchlogs.DescribeLogGroupsInput{}
+
+	pattern, err := cmd.Flags().GetString("pattern")
+	utils.CheckErr(err)
+	if pattern != "" {
+		describeParams.LogGroupNamePattern = &pattern
+	}
+
+	prefix, err := cmd.Flags().GetString("prefix")
+	utils.CheckErr(err)
+	if prefix != "" {
+		describeParams.LogGroupNamePrefix = &prefix
+	}
+
+	if pattern == "" && prefix == "" {
+		utils.CheckErr(fmt.Errorf("a log group name, prefix or pattern is required"))
+	}
+
+	allGroups, err := cmd.Flags().GetBool("all-groups")
+	utils.CheckErr(err)
+	limitGroups, err := cmd.Flags().GetInt32("limit-groups")
+	utils.CheckErr(err)
+
+	// Request describe
+	groupsFetcher := fetch.NewGroupsFetcher(
+		ctx,
+		&fetch.GroupsFetcherClient{
+			Client: client,
+			Params: *describeParams,
+		},
+	)
+	if !allGroups {
+		groupsFetcher = groupsFetcher.WithLimit(limitGroups)
+	}
+	logGroups, err := groupsFetcher.All()
+	checkCtxErr(ctx, err)
+
+	// More groups match if there is a next page after the limit
+	if groupsFetcher.NextToken() != nil {
+		if strict {
+			utils.CheckErr(fmt.Errorf(
+				"more than %d log groups match, use --all-groups to select all of them or --limit-groups to raise the limit",
+				limitGroups,
+			))
+		}
+		style.PrintWarning("Only the first %d matching log groups are used, use --all-groups to use all of them", limitGroups)
+	}
+
+	return logGroups
+}
+
+// Find the log group with the exact given name
+func describeLogGroup(
+	ctx context.Context,
+	client fetch.GroupsDescriber,
+	name string,
+) (types.LogGroup, error) {
+	groupsFetcher := fetch.NewGroupsFetcher(
+		ctx,
+		&fetch.GroupsFetcherClient{
+			Client: client,
+			Params: cloudwatchlogs.DescribeLogGroupsInput{
+				LogGroupNamePrefix: &name,
+			},
+		},
+	)
+
+	for groupsFetcher.HasNextPage() {
+		groups, err := groupsFetcher.NextPage()
+		if err != nil {
+			return types.LogGroup{}, err
+		}
+		for _, group := range groups {
+			if *group.LogGroupName == name {
+				return group, nil
+			}
+		}
+	}
+
+	return types.LogGroup{}, fmt.Errorf("log group '%s' not found", name)
+}
@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/ui/chart"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	chartBars      = "bars"
+	chartSparkline = "sparkline"
+
+	histogramBarWidth = 50
+	// Maximum number of buckets of a histogram
+	histogramMaxBuckets = 1000
+	// Layout of bin() values in Insights results
+	insightsTimeLayout = "2006-01-02 15:04:05.000"
+)
+
+func init() {
+	addGroupSelectionFlags(logsHistogramCommand)
+
+	logsHistogramCommand.Flags().StringP("filter", "f", "", "pattern filter on log events")
+	logsHistogramCommand.Flags().String("since", "1d", "moment in time to start counting, can be absolute or relative")
+	logsHistogramCommand.Flags().String("until", "0s", "moment in time to end counting, can be absolute or relative")
+	logsHistogramCommand.Flags().Duration("bucket", 5*time.Minute, "duration of the time buckets events are counted in")
+
+	logsHistogramCommand.Flags().String("chart", "", "chart to draw, bars or sparkline, defaults to bars for a single group and sparkline otherwise")
+	logsHistogramCommand.Flags().Bool("client-side", false, "count fetched events instead of running an Insights query")
+}
+
+var logsHistogramCommand = &cobra.Command{
+	Use:   "histogram [group...]",
+	Short: "Count log events matching a filter per time bucket and draw them as a chart",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		now := time.Now()
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroups(ctx, cmd, client, args)
+		if len(logGroups) == 0 {
+			style.PrintInfo("No groups found")
+			return
+		}
+
+		filter, err := cmd.Flags().GetString("filter")
+		utils.CheckErr(err)
+		bucket, err := cmd.Flags().GetDuration("bucket")
+		utils.CheckErr(err)
+		chartType, err := cmd.Flags().GetString("chart")
+		utils.CheckErr(err)
+		clientSide, err := cmd.Flags().GetBool("client-side")
+		utils.CheckErr(err)
+
+		switch chartType {
+		case "":
+			chartType = chartSparkline
+			if len(logGroups) == 1 {
+				chartType = chartBars
+			}
+		case chartBars, chartSparkline:
+		default:
+			utils.CheckErr(fmt.Errorf("invalid chart '%s', expected bars or sparkline", chartType))
+		}
+
+		sinceUnix := getTimestampFlag(cmd, "since", now)
+		untilUnix := getTimestampFlag(cmd, "until", now)
+		histogram, err := newHistogram(sinceUnix, untilUnix, bucket)
+		utils.CheckErr(err)
+
+		// Insights counts events much faster, but only supports filters
+		// which can be translated to its syntax
+		queryFilter, ok := insightsFilter(filter)
+		if !clientSide && !ok {
+			style.PrintInfo("Filter cannot be used in an Insights query, counting events on the client side")
+			clientSide = true
+		}
+		if !clientSide {
+			err = countWithInsights(ctx, client, logGroups, queryFilter, &histogram, sinceUnix, untilUnix)
+			fmt.Fprintln(os.Stderr)
+			exitIfDone(ctx)
+			if err != nil {
+				style.PrintWarning("Insights query failed, counting events on the client side: %s", utils.ErrorReason(err))
+				histogram, _ = newHistogram(sinceUnix, untilUnix, bucket)
+				clientSide = true
+			}
+		}
+		if clientSide {
+			err = countWithFetcher(ctx, client, logGroups, filter, &histogram, sinceUnix, untilUnix)
+			fmt.Fprintln(os.Stderr)
+			checkCtxErr(ctx, err)
+		}
+
+		names := []string{}
+		for _, group := range logGroups {
+			names = append(names, *group.LogGroupName)
+		}
+
+		if outputFormat != tables.FormatText {
+			printHistogramTable(&histogram, names)
+			return
+		}
+		if chartType == chartBars {
+			printHistogramBars(&histogram, names)
+		} else {
+			printHistogramSparklines(&histogram, names)
+		}
+	},
+}
+
+// Histogram of the given range, checking that the range is not empty and has
+// at most histogramMaxBuckets buckets
+func newHistogram(since int64, until int64, bucket time.Duration) (chart.Histogram, error) {
+	if since >= until {
+		return chart.Histogram{}, fmt.Errorf("--since must be earlier than --until")
+	}
+	if bucket.Milliseconds() <= 0 {
+		return chart.Histogram{}, fmt.Errorf("invalid bucket '%s', expected a duration of at least 1ms", bucket)
+	}
+
+	histogram := chart.NewHistogram(since, until, bucket)
+	if histogram.Size > histogramMaxBuckets {
+		return chart.Histogram{}, fmt.Errorf(
+			"%d buckets between --since and --until, use a larger --bucket to have at most %d",
+			histogram.Size,
+			histogramMaxBuckets,
+		)
+	}
+	return histogram, nil
+}
+
+// Terms without the operators of filter patterns, such as ?optional or -excluded
+var plainFilterPattern = regexp.MustCompile(`^[\w.:/@][\w.:/@-]*(\s+[\w.:/@][\w.:/@-]*)*$`)
+
+// Translate a filter pattern made of plain terms, or a single quoted phrase,
+// to an Insights filter command, report false for other patterns
+func insightsFilter(filter string) (string, bool) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return "", true
+	}
+
+	terms := []string{}
+	if phrase, err := strconv.Unquote(filter); err == nil && strings.HasPrefix(filter, `"`) {
+		terms = append(terms, phrase)
+	} else if plainFilterPattern.MatchString(filter) {
+		terms = strings.Fields(filter)
+	} else {
+		return "", false
+	}
+
+	conditions := []string{}
+	for _, term := range terms {
+		conditions = append(conditions, "@message like "+strconv.Quote(term))
+	}
+	return "| filter " + strings.Join(conditions, " and "), true
+}
+
+// Count events per bucket with Insights queries, of up to QUERY_MAX_GROUPS
+// groups each and of few enough groups that a row for each of their buckets
+// fits in QUERY_MAX_ROWS. Queries returning as many rows as the limit are
+// reported as errors, since counts may be missing.
+func countWithInsights(
+	ctx context.Context,
+	client *cloudwatchlogs.Client,
+	logGroups []types.LogGroup,
+	queryFilter string,
+	histogram *chart.Histogram,
+	since int64,
+	until int64,
+) error {
+	bin := fmt.Sprintf("bin(%s)", histogram.Bin())
+	queryString := fmt.Sprintf("fields @timestamp %s | stats count() as count by %s, @log", queryFilter, bin)
+
+	batchSize := min(fetch.QUERY_MAX_GROUPS, max(fetch.QUERY_MAX_ROWS/histogram.Size, 1))
+	for start := 0; start < len(logGroups); start += batchSize {
+		batch := logGroups[start:min(start+batchSize, len(logGroups))]
+
+		identifiers := []string{}
+		for _, group := range batch {
+			identifiers = append(identifiers, *group.LogGroupArn)
+		}
+
+		query := fetch.NewQuery(
+			client,
+			cloudwatchlogs.StartQueryInput{
+				QueryString:         &queryString,
+				LogGroupIdentifiers: identifiers,
+				// Insights times are in seconds
+				StartTime: aws.Int64(since / 1000),
+				EndTime:   aws.Int64(until / 1000),
+				Limit:     aws.Int32(fetch.QUERY_MAX_ROWS),
+			},
+		)
+		query.OnProgress = func(status types.QueryStatus, statistics *types.QueryStatistics) {
+			if statistics == nil {
+				style.PrintProgress("%s", status)
+				return
+			}
+			style.PrintProgress("%s: %.0f records scanned", status, statistics.RecordsScanned)
+		}
+
+		results, err := query.Run(ctx)
+		if err != nil {
+			return err
+		}
+		if len(results.Rows) >= fetch.QUERY_MAX_ROWS {
+			return fmt.Errorf("more than %d buckets with events, use a larger --bucket", fetch.QUERY_MAX_ROWS)
+		}
+
+		for _, row := range results.Rows {
+			timestamp, err := time.ParseInLocation(insightsTimeLayout, row[bin], time.UTC)
+			if err != nil {
+				return fmt.Errorf("unexpected bin value '%s'", row[bin])
+			}
+			count, err := strconv.Atoi(row["count"])
+			if err != nil {
+				return fmt.Errorf("unexpected count value '%s'", row["count"])
+			}
+			// Log groups are identified as account:name
+			_, group, _ := strings.Cut(row["@log"], ":")
+			histogram.Add(group, timestamp.UnixMilli(), count)
+		}
+	}
+	return nil
+}
+
+// Count events per bucket fetching all matching events of each group
+func countWithFetcher(
+	ctx context.Context,
+	client *cloudwatchlogs.Client,
+	logGroups []types.LogGroup,
+	filter string,
+	histogram *chart.Histogram,
+	since int64,
+	until int64,
+) error {
+	for _, group := range logGroups {
+		logFetcher := fetch.NewLogsFetcher(
+			ctx,
+			&fetch.LogsFetcherClient{
+				Client: client,
+				Params: cloudwatchlogs.FilterLogEventsInput{
+					LogGroupName:  group.LogGroupName,
+					StartTime:     &since,
+					EndTime:       &until,
+					FilterPattern: &filter,
+				},
+			},
+		)
+
+		counted := 0
+		for event, err := range logFetcher.Items() {
+			if err != nil {
+				return err
+			}
+			histogram.Add(*group.LogGroupName, aws.ToInt64(event.Timestamp), 1)
+			counted++
+			if counted%1000 == 0 {
+				style.PrintProgress("%s: %d events counted", *group.LogGroupName, counted)
+			}
+		}
+		style.PrintProgress("%s: %d events counted", *group.LogGroupName, counted)
+	}
+	return nil
+}
+
+func formatBucket(timestamp int64) string {
+	return time.UnixMilli(timestamp).Format("2006-01-02 15:04")
+}
+
+// Draw a bar for each bucket of each group
+func printHistogramBars(histogram *chart.Histogram, names []string) {
+	for i, name := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(style.TitleStyle.Render(name))
+
+		counts := histogram.Counts[name]
+		if counts == nil {
+			counts = make([]int, histogram.Size)
+		}
+		scale := chart.Max(counts)
+		for j, count := range counts {
+			fmt.Printf(
+				"%s %s %d\n",
+				style.LogDate.Render(formatBucket(histogram.BucketStart(j))),
+				style.AccentStyle.Render(chart.Bar(count, scale, histogramBarWidth)),
+				count,
+			)
+		}
+	}
+}
+
+// Draw a sparkline for each group, with the same scale for all groups
+func printHistogramSparklines(histogram *chart.Histogram, names []string) {
+	scale := 1
+	width := 0
+	for _, name := range names {
+		scale = max(scale, chart.Max(histogram.Counts[name]))
+		width = max(width, len(name))
+	}
+
+	fmt.Println(style.StyleInfo(
+		"%s to %s, %s buckets, up to %d events per bucket",
+		formatBucket(histogram.BucketStart(0)),
+		formatBucket(histogram.BucketStart(histogram.Size)),
+		histogram.Bucket,
+		scale,
+	))
+	for _, name := range names {
+		counts := histogram.Counts[name]
+		if counts == nil {
+			counts = make([]int, histogram.Size)
+		}
+		total := 0
+		for _, count := range counts {
+			total += count
+		}
+		fmt.Printf(
+			"%s %s %d\n",
+			style.LogTitle.Render(fmt.Sprintf("%-*s", width, name)),
+			style.AccentStyle.Render(chart.Sparkline(counts, scale)),
+			total,
+		)
+	}
+}
+
+// Print a row for each bucket with a column of counts for each group
+func printHistogramTable(histogram *chart.Histogram, names []string) {
+	keyTime := "time"
+
+	columns := []tables.Column{
+		tables.NewColumn(keyTime, "Time", true),
+	}
+	for _, name := range names {
+		columns = append(columns, tables.NewColumn(name, name, true).WithAlignment(tables.Right))
+	}
+
+	rows := []tables.Row{}
+	for i := 0; i < histogram.Size; i++ {
+		row := tables.Row{
+			keyTime: time.UnixMilli(histogram.BucketStart(i)).UTC().Format(time.RFC3339),
+		}
+		for _, name := range names {
+			count := 0
+			if counts, ok := histogram.Counts[name]; ok {
+				count = counts[i]
+			}
+			row[name] = strconv.Itoa(count)
+		}
+		rows = append(rows, row)
+	}
+
+	printTable(tables.New(columns).WithRows(rows))
+}
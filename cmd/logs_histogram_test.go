@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsightsFilter(t *testing.T) {
+	cases := []struct {
+		filter string
+		query  string
+		ok     bool
+	}{
+		{"", "", true},
+		{"  ", "", true},
+		{"ERROR", `| filter @message like "ERROR"`, true},
+		{"ERROR timeout", `| filter @message like "ERROR" and @message like "timeout"`, true},
+		{"/api/users user@example.com", `| filter @message like "/api/users" and @message like "user@example.com"`, true},
+		{`"Connection reset"`, `| filter @message like "Connection reset"`, true},
+		{`"say \"hi\""`, `| filter @message like "say \"hi\""`, true},
+		// Operators of filter patterns have no plain translation
+		{"?ERROR ?WARN", "", false},
+		{"ERROR -healthcheck", "", false},
+		{`{ $.level = "error" }`, "", false},
+		{"[ip, user, status=5*]", "", false},
+		{`"unterminated`, "", false},
+	}
+	for _, c := range cases {
+		query, ok := insightsFilter(c.filter)
+		assert.Equal(t, c.ok, ok, c.filter)
+		assert.Equal(t, c.query, query, c.filter)
+	}
+}
+
+func TestNewHistogram(t *testing.T) {
+	hour := time.Hour.Milliseconds()
+
+	h, err := newHistogram(0, 24*hour, 5*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 289, h.Size)
+
+	_, err = newHistogram(2*hour, hour, 5*time.Minute)
+	assert.Error(t, err)
+	_, err = newHistogram(hour, hour, 5*time.Minute)
+	assert.Error(t, err)
+	_, err = newHistogram(0, hour, 0)
+	assert.Error(t, err)
+	_, err = newHistogram(0, hour, -time.Minute)
+	assert.Error(t, err)
+	_, err = newHistogram(0, hour, time.Microsecond)
+	assert.Error(t, err)
+	// Too many buckets
+	_, err = newHistogram(0, 24*hour, time.Minute)
+	assert.Error(t, err)
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -33,8 +32,10 @@ var logsListCommad = &cobra.Command{
 	Use:   "list",
 	Short: "List cloudwatch log groups",
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
 		// Load config
-		cfg, err := loadAwsConfig(context.TODO())
+		cfg, err := loadAwsConfig(ctx)
 		utils.CheckErr(err)
 
 		// Setup params using flags
@@ -62,7 +63,7 @@ var logsListCommad = &cobra.Command{
 		client := cloudwatchlogs.NewFromConfig(cfg)
 
 		groupsFetcher := fetch.NewGroupsFetcher(
-			context.TODO(),
+			ctx,
 			&fetch.GroupsFetcherClient{
 				Client: client,
 				Params: *params,
@@ -72,7 +73,7 @@ var logsListCommad = &cobra.Command{
 			groupsFetcher = groupsFetcher.WithLimit(limit)
 		}
 		logGroups, err := groupsFetcher.All()
-		utils.CheckErr(err)
+		checkCtxErr(ctx, err)
 
 		showStreams, err := cmd.Flags().GetBool("streams")
 		utils.CheckErr(err)
@@ -88,8 +89,8 @@ var logsListCommad = &cobra.Command{
 				params := &cloudwatchlogs.DescribeLogStreamsInput{
 					LogGroupName: group.LogGroupName,
 				}
-				logStreams, err := client.DescribeLogStreams(context.TODO(), params)
-				utils.CheckErr(err)
+				logStreams, err := client.DescribeLogStreams(ctx, params)
+				checkCtxErr(ctx, err)
 
 				var names = []string{}
 				for _, stream := range logStreams.LogStreams {
@@ -125,18 +126,12 @@ var logsListCommad = &cobra.Command{
 
 		rows := []tables.Row{}
 		for index, group := range logGroups {
-			var retention string
-			if group.RetentionInDays != nil {
-				retention = fmt.Sprintf("%d days", *group.RetentionInDays)
-			} else {
-				retention = "-"
-			}
 			rows = append(rows, tables.Row{
 				keyIndex:        fmt.Sprintf("%d", index+1),
 				keyCreationDate: time.UnixMilli(*group.CreationTime).Format("2006-01-02"),
 				keyName:         *group.LogGroupName,
 				keyArn:          *group.LogGroupArn,
-				keyRetention:    retention,
+				keyRetention:    formatRetention(group.RetentionInDays),
 				keyStreams:      strings.Join(streams[*group.LogGroupName], ", "),
 			})
 		}
@@ -144,6 +139,6 @@ var logsListCommad = &cobra.Command{
 		table := tables.New(columns).WithRows(rows)
 
 		// Render Table
-		fmt.Println(table.Render())
+		printTable(table)
 	},
 }
@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addGroupSelectionFlags(logsMetricFiltersListCommand)
+	logsMetricFiltersListCommand.Flags().String("name-prefix", "", "prefix filter on metric filter name")
+
+	addGroupSelectionFlags(logsMetricFiltersCreateCommand)
+	addConfirmFlags(logsMetricFiltersCreateCommand)
+	logsMetricFiltersCreateCommand.Flags().StringP("name", "n", "", "name of the metric filter, an existing one is replaced")
+	logsMetricFiltersCreateCommand.Flags().StringP("filter", "f", "", "pattern filter on log events, all events match if empty")
+	logsMetricFiltersCreateCommand.Flags().String("namespace", "", "namespace of the metric")
+	logsMetricFiltersCreateCommand.Flags().String("metric", "", "name of the metric")
+	logsMetricFiltersCreateCommand.Flags().String("value", "1", "value emitted for matching events, a number or an extracted field such as $.latency")
+	logsMetricFiltersCreateCommand.Flags().Float64("default-value", 0, "value emitted when no event matches, none if not given")
+	logsMetricFiltersCreateCommand.Flags().String("unit", "", "unit of the metric, such as Count or Milliseconds")
+	logsMetricFiltersCreateCommand.Flags().StringArray("dimension", []string{}, "dimension of the metric as key=value, can be repeated")
+	logsMetricFiltersCreateCommand.MarkFlagRequired("name")
+	logsMetricFiltersCreateCommand.MarkFlagRequired("namespace")
+	logsMetricFiltersCreateCommand.MarkFlagRequired("metric")
+
+	addGroupSelectionFlags(logsMetricFiltersTestCommand)
+	addPatternTestFlags(logsMetricFiltersTestCommand)
+	logsMetricFiltersTestCommand.Flags().StringP("name", "n", "", "name of an existing metric filter to test")
+	logsMetricFiltersTestCommand.Flags().StringP("filter", "f", "", "pattern filter to test")
+	logsMetricFiltersTestCommand.Flags().String("value", "1", "value emitted for events matching the pattern filter, a number or an extracted field such as $.latency")
+	logsMetricFiltersTestCommand.MarkFlagsOneRequired("name", "filter")
+	logsMetricFiltersTestCommand.MarkFlagsMutuallyExclusive("name", "filter")
+	logsMetricFiltersTestCommand.MarkFlagsMutuallyExclusive("name", "value")
+
+	addGroupSelectionFlags(logsMetricFiltersDeleteCommand)
+	addConfirmFlags(logsMetricFiltersDeleteCommand)
+	logsMetricFiltersDeleteCommand.Flags().StringP("name", "n", "", "name of the metric filter to delete")
+	logsMetricFiltersDeleteCommand.MarkFlagRequired("name")
+}
+
+var logsMetricFiltersCommand = &cobra.Command{
+	Use:   "metric-filters",
+	Short: "Manage metric filters of cloudwatch log groups",
+}
+
+var logsMetricFiltersListCommand = &cobra.Command{
+	Use:   "list [group...]",
+	Short: "List metric filters of log groups",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		namePrefix, err := cmd.Flags().GetString("name-prefix")
+		utils.CheckErr(err)
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroups(ctx, cmd, client, args)
+		filters := fetchMetricFilters(ctx, client, logGroups, namePrefix)
+
+		// Setup table
+		var (
+			keyIndex        = "index"
+			keyGroup        = "group"
+			keyName         = "name"
+			keyPattern      = "pattern"
+			keyMetric       = "metric"
+			keyValue        = "value"
+			keyDefault      = "default"
+			keyUnit         = "unit"
+			keyDimensions   = "dimensions"
+			keyCreationDate = "creation"
+		)
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyGroup, "Group", true),
+			tables.NewColumn(keyName, "Name", true),
+			tables.NewColumn(keyPattern, "Pattern", true),
+			tables.NewColumn(keyMetric, "Metric", true),
+			tables.NewColumn(keyValue, "Value", true),
+			tables.NewColumn(keyDefault, "Default", false),
+			tables.NewColumn(keyUnit, "Unit", false),
+			tables.NewColumn(keyDimensions, "Dimensions", false),
+			tables.NewColumn(keyCreationDate, "Creation", false),
+		}
+
+		rows := []tables.Row{}
+		for _, group := range logGroups {
+			for _, filter := range filters[*group.LogGroupName] {
+				row := tables.Row{
+					keyIndex:        fmt.Sprintf("%d", len(rows)+1),
+					keyGroup:        *group.LogGroupName,
+					keyName:         aws.ToString(filter.FilterName),
+					keyPattern:      aws.ToString(filter.FilterPattern),
+					keyCreationDate: utils.FormatTimestamp(filter.CreationTime, time.DateOnly),
+				}
+				// A metric filter has a single transformation
+				if len(filter.MetricTransformations) > 0 {
+					t := filter.MetricTransformations[0]
+					row[keyMetric] = formatMetric(t)
+					row[keyValue] = aws.ToString(t.MetricValue)
+					row[keyDefault] = formatDefaultValue(t.DefaultValue)
+					row[keyUnit] = string(t.Unit)
+					row[keyDimensions] = formatTags(t.Dimensions)
+				}
+				rows = append(rows, row)
+			}
+		}
+
+		if len(rows) == 0 {
+			style.PrintInfo("No metric filters found in %d groups", len(logGroups))
+			return
+		}
+
+		printTable(tables.New(columns).WithRows(rows))
+	},
+}
+
+var logsMetricFiltersCreateCommand = &cobra.Command{
+	Use:   "create [group...]",
+	Short: "Create or replace a metric filter of log groups",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		name, err := cmd.Flags().GetString("name")
+		utils.CheckErr(err)
+		pattern, err := cmd.Flags().GetString("filter")
+		utils.CheckErr(err)
+		namespace, err := cmd.Flags().GetString("namespace")
+		utils.CheckErr(err)
+		metric, err := cmd.Flags().GetString("metric")
+		utils.CheckErr(err)
+		value, err := cmd.Flags().GetString("value")
+		utils.CheckErr(err)
+		unit, err := cmd.Flags().GetString("unit")
+		utils.CheckErr(err)
+		pairs, err := cmd.Flags().GetStringArray("dimension")
+		utils.CheckErr(err)
+		dimensions, err := parseTags(pairs)
+		utils.CheckErr(err)
+
+		transformation := types.MetricTransformation{
+			MetricName:      &metric,
+			MetricNamespace: &namespace,
+			MetricValue:     &value,
+			Unit:            types.StandardUnit(unit),
+		}
+		if len(dimensions) > 0 {
+			transformation.Dimensions = dimensions
+		}
+		if cmd.Flags().Changed("default-value") {
+			defaultValue, err := cmd.Flags().GetFloat64("default-value")
+			utils.CheckErr(err)
+			transformation.DefaultValue = &defaultValue
+		}
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		// Syntax errors are otherwise only found once events do not match
+		checkFilterPattern(ctx, client, pattern)
+
+		logGroups := selectLogGroupsToChange(ctx, cmd, client, args)
+		if len(logGroups) == 0 {
+			style.PrintInfo("No groups found")
+			return
+		}
+		existing := fetchMetricFilters(ctx, client, logGroups, name)
+
+		// Setup table
+		var (
+			keyIndex   = "index"
+			keyGroup   = "group"
+			keyAction  = "action"
+			keyPattern = "pattern"
+			keyMetric  = "metric"
+			keyValue   = "value"
+			keyCurrent = "current"
+		)
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyGroup, "Group", true),
+			tables.NewColumn(keyAction, "Action", true),
+			tables.NewColumn(keyPattern, "Pattern", true),
+			tables.NewColumn(keyMetric, "Metric", true),
+			tables.NewColumn(keyValue, "Value", true),
+			tables.NewColumn(keyCurrent, "Current Pattern", true),
+		}
+
+		rows := []tables.Row{}
+		names := []string{}
+		for index, group := range logGroups {
+			action, current := "create", "-"
+			for _, filter := range existing[*group.LogGroupName] {
+				if aws.ToString(filter.FilterName) == name {
+					action, current = "replace", aws.ToString(filter.FilterPattern)
+				}
+			}
+			rows = append(rows, tables.Row{
+				keyIndex:   fmt.Sprintf("%d", index+1),
+				keyGroup:   *group.LogGroupName,
+				keyAction:  action,
+				keyPattern: pattern,
+				keyMetric:  formatMetric(transformation),
+				keyValue:   value,
+				keyCurrent: current,
+			})
+			names = append(names, *group.LogGroupName)
+		}
+
+		prompt := fmt.Sprintf("Create metric filter '%s' in %d groups?", name, len(names))
+		if !confirmChanges(ctx, cmd, tables.New(columns).WithRows(rows), prompt) {
+			return
+		}
+
+		applyToGroups(ctx, names, "Create metric filter", func(group string) error {
+			_, err := client.PutMetricFilter(ctx, &cloudwatchlogs.PutMetricFilterInput{
+				LogGroupName:          &group,
+				FilterName:            &name,
+				FilterPattern:         &pattern,
+				MetricTransformations: []types.MetricTransformation{transformation},
+			})
+			return err
+		})
+	},
+}
+
+var logsMetricFiltersTestCommand = &cobra.Command{
+	Use:   "test [group...]",
+	Short: "Test a metric filter on recent events of log groups and show the values matching events emit",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		name, err := cmd.Flags().GetString("name")
+		utils.CheckErr(err)
+		pattern, err := cmd.Flags().GetString("filter")
+		utils.CheckErr(err)
+		value, err := cmd.Flags().GetString("value")
+		utils.CheckErr(err)
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroups(ctx, cmd, client, args)
+		if len(logGroups) == 0 {
+			style.PrintInfo("No groups found")
+			return
+		}
+
+		patterns := map[string]groupPattern{}
+		if name == "" {
+			checkFilterPattern(ctx, client, pattern)
+			for _, group := range logGroups {
+				patterns[*group.LogGroupName] = groupPattern{pattern: pattern, metricValue: &value}
+			}
+		} else {
+			filters := fetchMetricFilters(ctx, client, logGroups, name)
+			for _, group := range logGroups {
+				for _, filter := range filters[*group.LogGroupName] {
+					if aws.ToString(filter.FilterName) != name || len(filter.MetricTransformations) == 0 {
+						continue
+					}
+					patterns[*group.LogGroupName] = groupPattern{
+						pattern:     aws.ToString(filter.FilterPattern),
+						metricValue: filter.MetricTransformations[0].MetricValue,
+					}
+				}
+			}
+			if len(patterns) == 0 {
+				style.PrintInfo("No metric filter '%s' found in %d groups", name, len(logGroups))
+				return
+			}
+		}
+
+		testGroupPatterns(ctx, cmd, client, logGroups, patterns)
+	},
+}
+
+var logsMetricFiltersDeleteCommand = &cobra.Command{
+	Use:   "delete [group...]",
+	Short: "Delete a metric filter of log groups",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		name, err := cmd.Flags().GetString("name")
+		utils.CheckErr(err)
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroupsToChange(ctx, cmd, client, args)
+		filters := fetchMetricFilters(ctx, client, logGroups, name)
+
+		// Setup table
+		var (
+			keyIndex   = "index"
+			keyGroup   = "group"
+			keyPattern = "pattern"
+			keyMetric  = "metric"
+		)
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyGroup, "Group", true),
+			tables.NewColumn(keyPattern, "Pattern", true),
+			tables.NewColumn(keyMetric, "Metric", true),
+		}
+
+		rows := []tables.Row{}
+		names := []string{}
+		for _, group := range logGroups {
+			for _, filter := range filters[*group.LogGroupName] {
+				if aws.ToString(filter.FilterName) != name {
+					continue
+				}
+				row := tables.Row{
+					keyIndex:   fmt.Sprintf("%d", len(rows)+1),
+					keyGroup:   *group.LogGroupName,
+					keyPattern: aws.ToString(filter.FilterPattern),
+				}
+				if len(filter.MetricTransformations) > 0 {
+					row[keyMetric] = formatMetric(filter.MetricTransformations[0])
+				}
+				rows = append(rows, row)
+				names = append(names, *group.LogGroupName)
+			}
+		}
+		if len(names) == 0 {
+			style.PrintInfo("No metric filter '%s' found in %d groups", name, len(logGroups))
+			return
+		}
+
+		prompt := fmt.Sprintf("Delete metric filter '%s' of %d groups?", name, len(names))
+		if !confirmChanges(ctx, cmd, tables.New(columns).WithRows(rows), prompt) {
+			return
+		}
+
+		applyToGroups(ctx, names, "Delete metric filter", func(group string) error {
+			_, err := client.DeleteMetricFilter(ctx, &cloudwatchlogs.DeleteMetricFilterInput{
+				LogGroupName: &group,
+				FilterName:   &name,
+			})
+			return err
+		})
+	},
+}
+
+// Metric of a transformation as namespace/name
+func formatMetric(t types.MetricTransformation) string {
+	return strings.Join([]string{aws.ToString(t.MetricNamespace), aws.ToString(t.MetricName)}, "/")
+}
+
+func formatDefaultValue(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
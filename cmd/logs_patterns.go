@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+// Maximum length of templates and examples in text output
+const patternTextWidth = 100
+
+func init() {
+	addGroupSelectionFlags(logsPatternsCommand)
+
+	logsPatternsCommand.Flags().BoolP("all", "a", false, "do not limit of log events to fetch from each group")
+	logsPatternsCommand.Flags().Int32P("limit", "l", 10000, "limit number of log events to fetch from each group")
+
+	logsPatternsCommand.Flags().StringP("filter", "f", "", "pattern filter on log events")
+	logsPatternsCommand.Flags().String("since", "1d", "moment in time to start the search, can be absolute or relative")
+	logsPatternsCommand.Flags().String("until", "0s", "moment in time to end the search, can be absolute or relative")
+
+	addStreamFlags(logsPatternsCommand)
+	addEventFlags(logsPatternsCommand)
+
+	logsPatternsCommand.Flags().IntP("top", "n", 50, "number of most frequent templates to show, all if 0")
+	logsPatternsCommand.Flags().Int("max-par", 5, "maximum parallelization for fetching")
+
+	logsPatternsCommand.MarkFlagsMutuallyExclusive("all", "limit")
+}
+
+var logsPatternsCommand = &cobra.Command{
+	Use:   "patterns [group...]",
+	Short: "Group log events into templates by masking variable tokens and show the most frequent ones",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		now := time.Now()
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroups(ctx, cmd, client, args)
+		if len(logGroups) == 0 {
+			style.PrintInfo("No groups found")
+			return
+		}
+
+		filter, err := cmd.Flags().GetString("filter")
+		utils.CheckErr(err)
+		filter = getFilterPattern(cmd, filter)
+		limitEvents, err := cmd.Flags().GetInt32("limit")
+		utils.CheckErr(err)
+		allEvents, err := cmd.Flags().GetBool("all")
+		utils.CheckErr(err)
+		top, err := cmd.Flags().GetInt("top")
+		utils.CheckErr(err)
+		maxPar, err := cmd.Flags().GetInt("max-par")
+		utils.CheckErr(err)
+
+		sinceUnix := getTimestampFlag(cmd, "since", now)
+		untilUnix := getTimestampFlag(cmd, "until", now)
+
+		streamNames, streamPrefix := getStreamFlags(cmd)
+
+		fetchers := []fetch.LogsFetcher{}
+		for _, group := range logGroups {
+			fetcher := fetch.NewLogsFetcher(
+				ctx,
+				&fetch.LogsFetcherClient{
+					Client: client,
+					Params: cloudwatchlogs.FilterLogEventsInput{
+						LogGroupName:        group.LogGroupName,
+						StartTime:           &sinceUnix,
+						EndTime:             &untilUnix,
+						FilterPattern:       &filter,
+						LogStreamNames:      streamNames,
+						LogStreamNamePrefix: streamPrefix,
+					},
+				},
+			)
+			if !allEvents {
+				fetcher = fetcher.WithLimit(limitEvents)
+			}
+			fetchers = append(fetchers, fetcher)
+		}
+
+		// Events are merged in time order so that multi-line events can be
+		// joined before they are grouped
+		patterns := tlog.NewPatterns()
+		events := newJoiner(cmd, newLevelFilter(cmd, patterns))
+		fetched := 0
+		err = fetch.Merge(
+			fetchers,
+			maxPar,
+			func(a, b types.FilteredLogEvent) bool {
+				return aws.ToInt64(a.Timestamp) < aws.ToInt64(b.Timestamp)
+			},
+			func(i int, event types.FilteredLogEvent) error {
+				fetched++
+				if fetched%1000 == 0 {
+					style.PrintProgress("%d events fetched", fetched)
+				}
+				log := utils.LogFromCloudwatchEvent(logGroups[i].LogGroupName, &event)
+				return events.Render(&log)
+			},
+		)
+		utils.CheckErr(tlog.Flush(events))
+		style.PrintProgress("%d events fetched", fetched)
+		fmt.Fprintln(os.Stderr)
+
+		exitIfDone(ctx)
+
+		partial := reportMergeError(err, logGroups)
+
+		if patterns.Total == 0 {
+			style.PrintInfo("No events found")
+		} else {
+			printPatterns(patterns, top)
+		}
+
+		if partial {
+			os.Exit(utils.EXIT_PARTIAL)
+		}
+	},
+}
+
+// Print the top most frequent patterns as a table, or all of them if top is
+// not positive
+func printPatterns(patterns *tlog.Patterns, top int) {
+	sorted := patterns.Sorted()
+	if top > 0 && len(sorted) > top {
+		style.PrintInfo("Showing %d most frequent out of %d templates", top, len(sorted))
+		sorted = sorted[:top]
+	}
+
+	var (
+		keyIndex     = "index"
+		keyCount     = "count"
+		keyShare     = "share"
+		keyTemplate  = "template"
+		keyExample   = "example"
+		keyGroup     = "group"
+		keyFirstSeen = "first_seen"
+		keyLastSeen  = "last_seen"
+	)
+
+	columns := []tables.Column{
+		tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+		tables.NewColumn(keyCount, "Count", true).WithAlignment(tables.Right),
+		tables.NewColumn(keyShare, "Share", true).WithAlignment(tables.Right),
+		tables.NewColumn(keyTemplate, "Template", true),
+		tables.NewColumn(keyExample, "Example", true),
+		tables.NewColumn(keyGroup, "Example Group", false),
+		tables.NewColumn(keyFirstSeen, "First Seen", true),
+		tables.NewColumn(keyLastSeen, "Last Seen", true),
+	}
+
+	rows := []tables.Row{}
+	for index, pattern := range sorted {
+		template := pattern.Template
+		example := aws.ToString(pattern.Example.Message)
+		if outputFormat == tables.FormatText {
+			template = truncateText(template, patternTextWidth)
+			example = truncateText(example, patternTextWidth)
+		}
+
+		rows = append(rows, tables.Row{
+			keyIndex:     fmt.Sprintf("%d", index+1),
+			keyCount:     fmt.Sprintf("%d", pattern.Count),
+			keyShare:     fmt.Sprintf("%.1f%%", 100*float64(pattern.Count)/float64(patterns.Total)),
+			keyTemplate:  template,
+			keyExample:   example,
+			keyGroup:     aws.ToString(pattern.Example.GroupName),
+			keyFirstSeen: utils.FormatTimestamp(&pattern.FirstSeen, time.DateTime),
+			keyLastSeen:  utils.FormatTimestamp(&pattern.LastSeen, time.DateTime),
+		})
+	}
+
+	printTable(tables.New(columns).WithRows(rows))
+}
+
+// First line of the text, cut to width runes
+func truncateText(text string, width int) string {
+	line, _, multiline := strings.Cut(strings.TrimSpace(text), "\n")
+	runes := []rune(strings.TrimSpace(line))
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	if multiline {
+		return string(runes) + " …"
+	}
+	return string(runes)
+}
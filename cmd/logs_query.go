@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/queries"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	logsQueryCommand.Flags().StringSliceP("group", "g", []string{}, "name of a log group to query, can be repeated")
+	addGroupSelectionFlags(logsQueryCommand)
+
+	logsQueryCommand.Flags().Int32P("limit", "l", 1000, "limit number of results to return")
+
+	logsQueryCommand.Flags().String("since", "1d", "moment in time to start the query, can be absolute or relative")
+	logsQueryCommand.Flags().String("until", "0s", "moment in time to end the query, can be absolute or relative")
+
+	logsQueryCommand.Flags().StringArray("set", []string{}, "value of a saved query parameter as key=value, can be repeated")
+	logsQueryCommand.Flags().String("queries-file", "", "file of saved queries, defaults to awst/queries.yaml in the user config directory")
+	logsQueryCommand.Flags().Bool("list", false, "list saved queries")
+
+	logsQueryCommand.MarkFlagsMutuallyExclusive("group", "pattern")
+	logsQueryCommand.MarkFlagsMutuallyExclusive("group", "prefix")
+}
+
+var logsQueryCommand = &cobra.Command{
+	Use:   "query",
+	Short: "Run a cloudwatch logs insights query, or a saved one with @name, on the selected log groups",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		list, err := cmd.Flags().GetBool("list")
+		utils.CheckErr(err)
+		if list {
+			printSavedQueries(cmd)
+			return
+		}
+		if len(args) == 0 {
+			utils.CheckErr(fmt.Errorf("a query or the name of a saved query is required"))
+		}
+
+		queryString := args[0]
+		if name, ok := strings.CutPrefix(queryString, "@"); ok {
+			queryString = applySavedQuery(cmd, name)
+		}
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		now := time.Now()
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		// Select log groups
+		groupNames, err := cmd.Flags().GetStringSlice("group")
+		utils.CheckErr(err)
+
+		logGroups := selectLogGroups(ctx, cmd, client, groupNames)
+		if len(logGroups) == 0 {
+			style.PrintInfo("No groups found")
+			return
+		}
+		if len(logGroups) > fetch.QUERY_MAX_GROUPS {
+			utils.CheckErr(fmt.Errorf(
+				"%d groups found, a query can search at most %d groups",
+				len(logGroups),
+				fetch.QUERY_MAX_GROUPS,
+			))
+		}
+
+		style.PrintInfo("Querying %d groups", len(logGroups))
+
+		// Setup params
+		limit, err := cmd.Flags().GetInt32("limit")
+		utils.CheckErr(err)
+
+		// Insights works with timestamps in seconds
+		sinceUnix := getTimestampFlag(cmd, "since", now) / 1000
+		untilUnix := getTimestampFlag(cmd, "until", now) / 1000
+
+		identifiers := []string{}
+		for _, group := range logGroups {
+			identifiers = append(identifiers, *group.LogGroupArn)
+		}
+
+		// Request
+		query := fetch.NewQuery(
+			client,
+			cloudwatchlogs.StartQueryInput{
+				QueryString:         &queryString,
+				LogGroupIdentifiers: identifiers,
+				StartTime:           &sinceUnix,
+				EndTime:             &untilUnix,
+				Limit:               &limit,
+			},
+		)
+		query.OnProgress = func(status types.QueryStatus, statistics *types.QueryStatistics) {
+			if statistics == nil {
+				style.PrintProgress("%s", status)
+				return
+			}
+			style.PrintProgress(
+				"%s: %.0f records matched, %.0f records scanned",
+				status,
+				statistics.RecordsMatched,
+				statistics.RecordsScanned,
+			)
+		}
+
+		results, err := query.Run(ctx)
+		fmt.Fprintln(os.Stderr)
+		checkCtxErr(ctx, err)
+
+		if len(results.Rows) == 0 {
+			style.PrintInfo("No results found")
+			return
+		}
+
+		// Setup table
+		var keyIndex = "index"
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+		}
+		for _, field := range results.Fields {
+			columns = append(columns, tables.NewColumn(field, field, true))
+		}
+
+		rows := []tables.Row{}
+		for index, result := range results.Rows {
+			row := tables.Row{
+				keyIndex: fmt.Sprintf("%d", index+1),
+			}
+			for field, value := range result {
+				row[field] = value
+			}
+			rows = append(rows, row)
+		}
+
+		table := tables.New(columns).WithRows(rows)
+
+		// Render table
+		printTable(table)
+	},
+}
+
+func loadSavedQueries(cmd *cobra.Command) queries.Library {
+	path, err := cmd.Flags().GetString("queries-file")
+	utils.CheckErr(err)
+	if path == "" {
+		path, err = queries.DefaultPath()
+		utils.CheckErr(err)
+	}
+
+	library, err := queries.Load(path)
+	utils.CheckErr(err)
+	return library
+}
+
+// Fill in the saved query with the given name and use its settings for the
+// flags not given on the command line, returning the query string
+func applySavedQuery(cmd *cobra.Command, name string) string {
+	saved, ok := loadSavedQueries(cmd)[name]
+	if !ok {
+		utils.CheckErr(fmt.Errorf("saved query '%s' not found", name))
+	}
+
+	pairs, err := cmd.Flags().GetStringArray("set")
+	utils.CheckErr(err)
+	params, err := queries.ParseParams(pairs)
+	utils.CheckErr(err)
+
+	saved, err = saved.Render(params)
+	utils.CheckErr(err)
+
+	setDefault := func(flag string, value string) {
+		if value != "" && !cmd.Flags().Changed(flag) {
+			utils.CheckErr(cmd.Flags().Set(flag, value))
+		}
+	}
+
+	selected := cmd.Flags().Changed("group") ||
+		cmd.Flags().Changed("pattern") ||
+		cmd.Flags().Changed("prefix")
+	if !selected {
+		setDefault("group", strings.Join(saved.Groups, ","))
+		setDefault("prefix", saved.Prefix)
+		setDefault("pattern", saved.Pattern)
+	}
+	setDefault("since", saved.Since)
+	setDefault("until", saved.Until)
+	if saved.Limit > 0 {
+		setDefault("limit", fmt.Sprintf("%d", saved.Limit))
+	}
+
+	return saved.Query
+}
+
+func printSavedQueries(cmd *cobra.Command) {
+	library := loadSavedQueries(cmd)
+	if len(library) == 0 {
+		style.PrintInfo("No saved queries found")
+		return
+	}
+
+	names := []string{}
+	for name := range library {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Setup table
+	var (
+		keyName        = "name"
+		keyDescription = "description"
+		keyGroups      = "groups"
+		keyParams      = "params"
+	)
+
+	columns := []tables.Column{
+		tables.NewColumn(keyName, "Name", true),
+		tables.NewColumn(keyDescription, "Description", true),
+		tables.NewColumn(keyGroups, "Groups", true),
+		tables.NewColumn(keyParams, "Parameters", true),
+	}
+
+	rows := []tables.Row{}
+	for _, name := range names {
+		saved := library[name]
+
+		var groups string
+		switch {
+		case len(saved.Groups) > 0:
+			groups = strings.Join(saved.Groups, ", ")
+		case saved.Prefix != "":
+			groups = saved.Prefix + "*"
+		default:
+			groups = saved.Pattern
+		}
+
+		params := []string{}
+		for key, value := range saved.Params {
+			params = append(params, fmt.Sprintf("%s=%s", key, value))
+		}
+		sort.Strings(params)
+
+		rows = append(rows, tables.Row{
+			keyName:        "@" + name,
+			keyDescription: saved.Description,
+			keyGroups:      groups,
+			keyParams:      strings.Join(params, ", "),
+		})
+	}
+
+	table := tables.New(columns).WithRows(rows)
+
+	// Render table
+	printTable(table)
+}
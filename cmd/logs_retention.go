@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+// Retention periods in days accepted by cloudwatch
+var retentionPeriods = []int32{
+	1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653,
+}
+
+func init() {
+	addGroupSelectionFlags(logsRetentionSetCommand)
+	addConfirmFlags(logsRetentionSetCommand)
+
+	logsRetentionSetCommand.Flags().Bool("only-unset", false, "change only log groups which never expire events")
+}
+
+var logsRetentionCommand = &cobra.Command{
+	Use:   "retention",
+	Short: "Manage retention of cloudwatch log groups",
+}
+
+var logsRetentionSetCommand = &cobra.Command{
+	Use:   "set [group...] days",
+	Short: "Set the number of days events of log groups are retained",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		days, err := parseRetention(args[len(args)-1])
+		utils.CheckErr(err)
+		onlyUnset, err := cmd.Flags().GetBool("only-unset")
+		utils.CheckErr(err)
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroupsToChange(ctx, cmd, client, args[:len(args)-1])
+
+		// Skip groups which would not change
+		changed := []types.LogGroup{}
+		for _, group := range logGroups {
+			if group.RetentionInDays != nil && (onlyUnset || *group.RetentionInDays == days) {
+				continue
+			}
+			changed = append(changed, group)
+		}
+		if len(changed) == 0 {
+			style.PrintInfo("No groups to change out of %d found", len(logGroups))
+			return
+		}
+
+		// Setup table
+		var (
+			keyIndex     = "index"
+			keyName      = "name"
+			keyCurrent   = "current"
+			keyRetention = "retention"
+			keyStored    = "stored"
+		)
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyName, "Name", true),
+			tables.NewColumn(keyCurrent, "Current Retention", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyRetention, "New Retention", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyStored, "Stored", false).WithAlignment(tables.Right),
+		}
+
+		rows := []tables.Row{}
+		names := []string{}
+		for index, group := range changed {
+			rows = append(rows, tables.Row{
+				keyIndex:     fmt.Sprintf("%d", index+1),
+				keyName:      *group.LogGroupName,
+				keyCurrent:   formatRetention(group.RetentionInDays),
+				keyRetention: formatRetention(&days),
+				keyStored:    formatStoredBytes(group.StoredBytes),
+			})
+			names = append(names, *group.LogGroupName)
+		}
+
+		prompt := fmt.Sprintf("Set retention of %d groups to %d days?", len(names), days)
+		if !confirmChanges(ctx, cmd, tables.New(columns).WithRows(rows), prompt) {
+			return
+		}
+
+		applyToGroups(ctx, names, "Set retention", func(name string) error {
+			_, err := client.PutRetentionPolicy(ctx, &cloudwatchlogs.PutRetentionPolicyInput{
+				LogGroupName:    &name,
+				RetentionInDays: &days,
+			})
+			return err
+		})
+	},
+}
+
+// Parse a retention period in days, which must be one accepted by cloudwatch
+func parseRetention(value string) (int32, error) {
+	days, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || !slices.Contains(retentionPeriods, int32(days)) {
+		return 0, fmt.Errorf("invalid retention '%s', expected one of %v days", value, retentionPeriods)
+	}
+	return int32(days), nil
+}
+
+func formatRetention(days *int32) string {
+	if days == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d days", *days)
+}
+
+func formatStoredBytes(bytes *int64) string {
+	if bytes == nil {
+		return "-"
+	}
+	return utils.FormatBytes(*bytes)
+}
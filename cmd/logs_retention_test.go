@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetention(t *testing.T) {
+	days, err := parseRetention("30")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(30), days)
+
+	days, err = parseRetention("3653")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3653), days)
+
+	for _, value := range []string{"0", "31", "-7", "30d", "", "99999999999"} {
+		_, err := parseRetention(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -1,12 +1,11 @@
 package cmd
 
 import (
-	"context"
-	"fmt"
-	"sort"
-	"sync"
+	"errors"
+	"os"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/ravvio/awst/fetch"
@@ -17,10 +16,7 @@ import (
 )
 
 func init() {
-	logsSearchCommand.Flags().StringP("pattern", "e", "", "pattern filter on log group name")
-	logsSearchCommand.Flags().StringP("prefix", "p", "", "prefix filter on log group name")
-	logsSearchCommand.Flags().Bool("all-groups", false, "do not limit of log groups to use")
-	logsSearchCommand.Flags().Int32("limit-groups", 50, "limit number of log groups to use")
+	addGroupSelectionFlags(logsSearchCommand)
 
 	logsSearchCommand.Flags().BoolP("all", "a", false, "do not limit of log events to fetch from each group")
 	logsSearchCommand.Flags().Int32P("limit", "l", 10000, "limit number of log events to fetch from each group")
@@ -29,13 +25,16 @@ func init() {
 	logsSearchCommand.Flags().String("since", "1d", "moment in time to start the search, can be absolute or relative")
 	logsSearchCommand.Flags().String("until", "0s", "moment in time to end the search, can be absolute or relative")
 
+	addStreamFlags(logsSearchCommand)
+	addRenderFlags(logsSearchCommand)
+	addContextFlags(logsSearchCommand)
+
 	logsSearchCommand.Flags().BoolP("tail", "t", false, "start live tail")
 
 	logsSearchCommand.Flags().Int("max-par", 5, "maximum parallelization for fetching")
 
 	logsSearchCommand.MarkFlagsOneRequired("pattern", "prefix")
 
-	logsSearchCommand.MarkFlagsMutuallyExclusive("pattern", "prefix")
 	logsSearchCommand.MarkFlagsMutuallyExclusive("all", "limit")
 }
 
@@ -43,47 +42,17 @@ var logsSearchCommand = &cobra.Command{
 	Use:   "search",
 	Short: "Search for cloudwatch log groups matching given pattern or prefix and retrive logs",
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
 		// Load config
-		cfg, err := loadAwsConfig(context.TODO())
+		cfg, err := loadAwsConfig(ctx)
 		utils.CheckErr(err)
 
 		now := time.Now()
 
 		client := cloudwatchlogs.NewFromConfig(cfg)
 
-		// Setup params for descibe operation
-		describeParams := &cloudwatchlogs.DescribeLogGroupsInput{}
-
-		pattern, err := cmd.Flags().GetString("pattern")
-		utils.CheckErr(err)
-		if pattern != "" {
-			describeParams.LogGroupNamePattern = &pattern
-		}
-
-		prefix, err := cmd.Flags().GetString("prefix")
-		utils.CheckErr(err)
-		if prefix != "" {
-			describeParams.LogGroupNamePrefix = &prefix
-		}
-
-		allGroups, err := cmd.Flags().GetBool("all-groups")
-		utils.CheckErr(err)
-		limitGroups, err := cmd.Flags().GetInt32("limit-groups")
-		utils.CheckErr(err)
-
-		// Request describe
-		groupsFetcher := fetch.NewGroupsFetcher(
-			context.TODO(),
-			&fetch.GroupsFetcherClient{
-				Client: client,
-				Params: *describeParams,
-			},
-		)
-		if !allGroups {
-			groupsFetcher = groupsFetcher.WithLimit(limitGroups)
-		}
-		logGroups, err := groupsFetcher.All()
-		utils.CheckErr(err)
+		logGroups := selectLogGroups(ctx, cmd, client, nil)
 
 		if len(logGroups) == 0 {
 			style.PrintInfo("No groups found")
@@ -95,6 +64,7 @@ var logsSearchCommand = &cobra.Command{
 		// Request logs
 		filter, err := cmd.Flags().GetString("filter")
 		utils.CheckErr(err)
+		filter = getFilterPattern(cmd, filter)
 		limitEvents, err := cmd.Flags().GetInt32("limit")
 		utils.CheckErr(err)
 		allEvents, err := cmd.Flags().GetBool("all")
@@ -102,155 +72,100 @@ var logsSearchCommand = &cobra.Command{
 		tail, err := cmd.Flags().GetBool("tail")
 		utils.CheckErr(err)
 
-		sinceDate, err := cmd.Flags().GetString("since")
-		var sinceUnix int64
-		utils.CheckErr(err)
-		if sinceDate != "" {
-			if t, err := utils.ParseDatetime(sinceDate); err == nil && t.UnixMilli() >= 0 {
-				sinceUnix = t.UnixMilli()
-			} else if d, err := utils.ParseDuration(sinceDate); err == nil {
-				sinceUnix = now.UnixMilli() - d
-			} else {
-				utils.CheckErr(fmt.Errorf("Could not parse 'since' timestamp"))
-			}
-		}
+		sinceUnix := getTimestampFlag(cmd, "since", now)
+		untilUnix := getTimestampFlag(cmd, "until", now)
 
-		untilDate, err := cmd.Flags().GetString("until")
-		var untilUnix int64
-		utils.CheckErr(err)
-		if untilDate != "" {
-			if t, err := utils.ParseDatetime(untilDate); err == nil && t.UnixMilli() >= 0 {
-				untilUnix = t.UnixMilli()
-			} else if d, err := utils.ParseDuration(untilDate); err == nil {
-				untilUnix = now.UnixMilli() - d
-			} else {
-				utils.CheckErr(fmt.Errorf("Could not parse 'until' timestamp"))
-			}
-		}
+		streamNames, streamPrefix := getStreamFlags(cmd)
 
 		maxPar, err := cmd.Flags().GetInt("max-par")
 		utils.CheckErr(err)
-		semaphore := make(chan struct{}, maxPar)
-
-		var wg sync.WaitGroup = sync.WaitGroup{}
 
-		logs := []tlog.Log{}
+		fetchers := []fetch.LogsFetcher{}
 		for _, group := range logGroups {
 			fetcher := fetch.NewLogsFetcher(
-				context.TODO(),
+				ctx,
 				&fetch.LogsFetcherClient{
 					Client: client,
 					Params: cloudwatchlogs.FilterLogEventsInput{
-						LogGroupName:  group.LogGroupName,
-						StartTime:     &sinceUnix,
-						EndTime:       &untilUnix,
-						FilterPattern: &filter,
+						LogGroupName:        group.LogGroupName,
+						StartTime:           &sinceUnix,
+						EndTime:             &untilUnix,
+						FilterPattern:       &filter,
+						LogStreamNames:      streamNames,
+						LogStreamNamePrefix: streamPrefix,
 					},
 				},
 			)
 			if !allEvents {
 				fetcher = fetcher.WithLimit(limitEvents)
 			}
-
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				res, err := fetcher.All()
-				utils.CheckErr(err)
-				for _, log := range res {
-					logs = append(
-						logs,
-						utils.LogFromCloudwatchEvent(group.LogGroupName, &log),
-					)
-				}
-				// Release semaphore
-				<-semaphore
-			}()
+			fetchers = append(fetchers, fetcher)
 		}
 
-		// Wait for all goroutines to finish fetching
-		wg.Wait()
+		// Pages of each group are in time order, merge them rendering
+		// events as soon as they can be placed
+		r := newLogRenderer(cmd)
+		events := newContextRenderer(ctx, cmd, client, newJoiner(cmd, r))
+		err = fetch.Merge(
+			fetchers,
+			maxPar,
+			func(a, b types.FilteredLogEvent) bool {
+				return aws.ToInt64(a.Timestamp) < aws.ToInt64(b.Timestamp)
+			},
+			func(i int, event types.FilteredLogEvent) error {
+				log := utils.LogFromCloudwatchEvent(logGroups[i].LogGroupName, &event)
+				return events.Render(&log)
+			},
+		)
+		utils.CheckErr(tlog.Flush(events))
 
-		sort.Slice(logs, func(i, j int) bool {
-			return *logs[j].Timestamp > *logs[i].Timestamp
-		})
+		exitIfDone(ctx)
 
-		r := tlog.DefaultRenderer()
-		for _, log := range logs {
-			err = r.Render(&log)
-			utils.CheckErr(err)
-		}
+		partial := reportMergeError(err, logGroups)
 
 		if !tail {
+			if partial {
+				os.Exit(utils.EXIT_PARTIAL)
+			}
 			return
 		}
 
-		logChan := make(chan tlog.Log)
-
-		// Create tail streams
-		i := 0
-		for {
-			n := min(i+10, len(logGroups))
-
-			identifiers := []string{}
-			for _, l := range logGroups[i:n] {
-				identifiers = append(identifiers, *l.LogGroupArn)
-			}
-
-			tailParams := &cloudwatchlogs.StartLiveTailInput{
-				// LogEventFilterPattern: &filter,
-				LogGroupIdentifiers:   identifiers,
-			}
-
-			tailOutput, err := client.StartLiveTail(
-				context.TODO(),
-				tailParams,
-			)
-			utils.CheckErr(err)
-
-			s := tailOutput.GetStream()
-			go handleStream(logChan, s)
-
-			i = n
-			if i >= len(logGroups) {
-				break
-			}
-		}
+		err = liveTail(
+			ctx,
+			fetch.CloudwatchLiveTailClient{Client: client},
+			logGroups,
+			liveTailParams(filter, streamNames, streamPrefix),
+			newJoiner(cmd, r),
+			getJoinWindow(cmd),
+		)
+		utils.CheckErr(err)
 
-		for {
-			log := <-logChan
-			r.Render(&log)
+		// Events fetched before the tail started are still partial
+		if partial {
+			os.Exit(utils.EXIT_PARTIAL)
 		}
-
+		exitIfDone(ctx)
 	},
 }
 
-func handleStream(
-	logChan chan tlog.Log,
-	eventStream *cloudwatchlogs.StartLiveTailEventStream,
-) {
-	eventsChan := eventStream.Events()
-	for {
-		if logChan != nil {
-			event := <-eventsChan
+// Report groups which could not be fetched by a merge without stopping,
+// returning whether results are partial. Other errors are fatal.
+func reportMergeError(err error, logGroups []types.LogGroup) bool {
+	var mergeErr *fetch.MergeError
+	if !errors.As(err, &mergeErr) {
+		utils.CheckErr(err)
+		return false
+	}
 
-			switch e := event.(type) {
-			case *types.StartLiveTailResponseStreamMemberSessionStart:
-				style.PrintInfo("Session %d start", e.Value.SessionId)
-			case *types.StartLiveTailResponseStreamMemberSessionUpdate:
-				for _, logEvent := range e.Value.SessionResults {
-					log := tlog.Log{
-						GroupName: logEvent.LogGroupIdentifier,
-						Timestamp: logEvent.Timestamp,
-						Message:   logEvent.Message,
-					}
-					logChan <- log
-				}
-			default:
-				utils.CheckErr(eventStream.Err())
-			}
+	style.PrintError(
+		"Results are partial, fetching events failed for %d out of %d groups",
+		len(mergeErr.Sources),
+		len(logGroups),
+	)
+	for i, group := range logGroups {
+		if err, ok := mergeErr.Sources[i]; ok {
+			style.PrintError("%s: %s", *group.LogGroupName, utils.ErrorReason(err))
 		}
 	}
+	return true
 }
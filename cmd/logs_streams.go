@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	logsStreamsCommand.Flags().BoolP("all", "a", false, "fetch all log streams")
+	logsStreamsCommand.Flags().Int32P("limit", "l", 50, "limit number of streams to fetch")
+
+	logsStreamsCommand.Flags().StringP("prefix", "p", "", "prefix filter on log stream name")
+	logsStreamsCommand.Flags().String("order-by", string(types.OrderByLogStreamName), "order streams by LogStreamName or LastEventTime")
+	logsStreamsCommand.Flags().BoolP("descending", "d", false, "return streams in descending order")
+
+	logsStreamsCommand.Flags().Bool("arn", false, "show log streams arn")
+
+	logsStreamsCommand.MarkFlagsMutuallyExclusive("all", "limit")
+}
+
+var logsStreamsCommand = &cobra.Command{
+	Use:   "streams",
+	Short: "List log streams of given log group",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		logGroupName := args[0]
+
+		// Setup params using flags
+		params := &cloudwatchlogs.DescribeLogStreamsInput{
+			LogGroupName: &logGroupName,
+		}
+
+		orderBy, err := cmd.Flags().GetString("order-by")
+		utils.CheckErr(err)
+		switch {
+		case strings.EqualFold(orderBy, string(types.OrderByLogStreamName)):
+			params.OrderBy = types.OrderByLogStreamName
+		case strings.EqualFold(orderBy, string(types.OrderByLastEventTime)):
+			params.OrderBy = types.OrderByLastEventTime
+		default:
+			utils.CheckErr(fmt.Errorf("invalid order '%s', expected LogStreamName or LastEventTime", orderBy))
+		}
+
+		prefix, err := cmd.Flags().GetString("prefix")
+		utils.CheckErr(err)
+		if prefix != "" {
+			if params.OrderBy == types.OrderByLastEventTime {
+				utils.CheckErr(fmt.Errorf("prefix filter cannot be used when ordering by LastEventTime"))
+			}
+			params.LogStreamNamePrefix = &prefix
+		}
+
+		descending, err := cmd.Flags().GetBool("descending")
+		utils.CheckErr(err)
+		params.Descending = &descending
+
+		// Request
+		all, err := cmd.Flags().GetBool("all")
+		utils.CheckErr(err)
+		limit, err := cmd.Flags().GetInt32("limit")
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		streamsFetcher := fetch.NewStreamsFetcher(
+			ctx,
+			&fetch.StreamsFetcherClient{
+				Client: client,
+				Params: *params,
+			},
+		)
+		if !all {
+			streamsFetcher = streamsFetcher.WithLimit(limit)
+		}
+		logStreams, err := streamsFetcher.All()
+		checkCtxErr(ctx, err)
+
+		if len(logStreams) == 0 {
+			style.PrintInfo("No streams found")
+			return
+		}
+
+		showArn, err := cmd.Flags().GetBool("arn")
+		utils.CheckErr(err)
+
+		// Setup table
+		var (
+			keyIndex         = "index"
+			keyName          = "name"
+			keyArn           = "arn"
+			keyFirstEvent    = "first_event"
+			keyLastEvent     = "last_event"
+			keyLastIngestion = "last_ingestion"
+			keyStoredBytes   = "stored_bytes"
+		)
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyName, "Name", true),
+			tables.NewColumn(keyArn, "Arn", showArn),
+			tables.NewColumn(keyFirstEvent, "First Event", true),
+			tables.NewColumn(keyLastEvent, "Last Event", true),
+			tables.NewColumn(keyLastIngestion, "Last Ingestion", true),
+			// No longer filled in by AWS, shown only with --all-columns
+			tables.NewColumn(keyStoredBytes, "Stored (deprecated)", false).WithAlignment(tables.Right),
+		}
+
+		rows := []tables.Row{}
+		for index, stream := range logStreams {
+			var storedBytes string
+			// Deprecated for log streams, reported as zero since June 2019
+			//nolint:staticcheck
+			if stream.StoredBytes != nil {
+				storedBytes = utils.FormatBytes(*stream.StoredBytes) //nolint:staticcheck
+			} else {
+				storedBytes = "-"
+			}
+			rows = append(rows, tables.Row{
+				keyIndex:         fmt.Sprintf("%d", index+1),
+				keyName:          *stream.LogStreamName,
+				keyArn:           *stream.Arn,
+				keyFirstEvent:    utils.FormatTimestamp(stream.FirstEventTimestamp, time.DateTime),
+				keyLastEvent:     utils.FormatTimestamp(stream.LastEventTimestamp, time.DateTime),
+				keyLastIngestion: utils.FormatTimestamp(stream.LastIngestionTime, time.DateTime),
+				keyStoredBytes:   storedBytes,
+			})
+		}
+
+		table := tables.New(columns).WithRows(rows)
+
+		// Render Table
+		printTable(table)
+	},
+}
@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addGroupSelectionFlags(logsSubscriptionsListCommand)
+	logsSubscriptionsListCommand.Flags().String("name-prefix", "", "prefix filter on subscription filter name")
+
+	addGroupSelectionFlags(logsSubscriptionsCreateCommand)
+	addConfirmFlags(logsSubscriptionsCreateCommand)
+	logsSubscriptionsCreateCommand.Flags().StringP("name", "n", "", "name of the subscription filter, an existing one is replaced")
+	logsSubscriptionsCreateCommand.Flags().StringP("filter", "f", "", "pattern filter on log events, all events match if empty")
+	logsSubscriptionsCreateCommand.Flags().String("destination", "", "ARN of the destination events are sent to, such as a Lambda function or a Kinesis stream")
+	logsSubscriptionsCreateCommand.Flags().String("role", "", "ARN of the role allowing to send events to the destination, not needed for Lambda functions")
+	logsSubscriptionsCreateCommand.Flags().String("distribution", "", "distribution of events to a Kinesis stream, ByLogStream or Random")
+	logsSubscriptionsCreateCommand.MarkFlagRequired("name")
+	logsSubscriptionsCreateCommand.MarkFlagRequired("destination")
+
+	addGroupSelectionFlags(logsSubscriptionsTestCommand)
+	addPatternTestFlags(logsSubscriptionsTestCommand)
+	logsSubscriptionsTestCommand.Flags().StringP("name", "n", "", "name of an existing subscription filter to test")
+	logsSubscriptionsTestCommand.Flags().StringP("filter", "f", "", "pattern filter to test")
+	logsSubscriptionsTestCommand.MarkFlagsOneRequired("name", "filter")
+	logsSubscriptionsTestCommand.MarkFlagsMutuallyExclusive("name", "filter")
+
+	addGroupSelectionFlags(logsSubscriptionsDeleteCommand)
+	addConfirmFlags(logsSubscriptionsDeleteCommand)
+	logsSubscriptionsDeleteCommand.Flags().StringP("name", "n", "", "name of the subscription filter to delete")
+	logsSubscriptionsDeleteCommand.MarkFlagRequired("name")
+}
+
+var logsSubscriptionsCommand = &cobra.Command{
+	Use:   "subscriptions",
+	Short: "Manage subscription filters of cloudwatch log groups",
+}
+
+var logsSubscriptionsListCommand = &cobra.Command{
+	Use:   "list [group...]",
+	Short: "List subscription filters of log groups",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		namePrefix, err := cmd.Flags().GetString("name-prefix")
+		utils.CheckErr(err)
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroups(ctx, cmd, client, args)
+		filters := fetchSubscriptionFilters(ctx, client, logGroups, namePrefix)
+
+		// Setup table
+		var (
+			keyIndex        = "index"
+			keyGroup        = "group"
+			keyName         = "name"
+			keyPattern      = "pattern"
+			keyDestination  = "destination"
+			keyRole         = "role"
+			keyDistribution = "distribution"
+			keyCreationDate = "creation"
+		)
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyGroup, "Group", true),
+			tables.NewColumn(keyName, "Name", true),
+			tables.NewColumn(keyPattern, "Pattern", true),
+			tables.NewColumn(keyDestination, "Destination", true),
+			tables.NewColumn(keyRole, "Role", false),
+			tables.NewColumn(keyDistribution, "Distribution", false),
+			tables.NewColumn(keyCreationDate, "Creation", false),
+		}
+
+		rows := []tables.Row{}
+		for _, group := range logGroups {
+			for _, filter := range filters[*group.LogGroupName] {
+				rows = append(rows, tables.Row{
+					keyIndex:        fmt.Sprintf("%d", len(rows)+1),
+					keyGroup:        *group.LogGroupName,
+					keyName:         aws.ToString(filter.FilterName),
+					keyPattern:      aws.ToString(filter.FilterPattern),
+					keyDestination:  aws.ToString(filter.DestinationArn),
+					keyRole:         aws.ToString(filter.RoleArn),
+					keyDistribution: string(filter.Distribution),
+					keyCreationDate: utils.FormatTimestamp(filter.CreationTime, time.DateOnly),
+				})
+			}
+		}
+
+		if len(rows) == 0 {
+			style.PrintInfo("No subscription filters found in %d groups", len(logGroups))
+			return
+		}
+
+		printTable(tables.New(columns).WithRows(rows))
+	},
+}
+
+var logsSubscriptionsCreateCommand = &cobra.Command{
+	Use:   "create [group...]",
+	Short: "Create or replace a subscription filter of log groups",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		name, err := cmd.Flags().GetString("name")
+		utils.CheckErr(err)
+		pattern, err := cmd.Flags().GetString("filter")
+		utils.CheckErr(err)
+		destination, err := cmd.Flags().GetString("destination")
+		utils.CheckErr(err)
+		role, err := cmd.Flags().GetString("role")
+		utils.CheckErr(err)
+		distribution, err := cmd.Flags().GetString("distribution")
+		utils.CheckErr(err)
+
+		params := cloudwatchlogs.PutSubscriptionFilterInput{
+			FilterName:     &name,
+			FilterPattern:  &pattern,
+			DestinationArn: &destination,
+		}
+		if role != "" {
+			params.RoleArn = &role
+		}
+		if distribution != "" {
+			params.Distribution, err = parseDistribution(distribution)
+			utils.CheckErr(err)
+		}
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		// Syntax errors are otherwise only found once events do not match
+		checkFilterPattern(ctx, client, pattern)
+
+		logGroups := selectLogGroupsToChange(ctx, cmd, client, args)
+		if len(logGroups) == 0 {
+			style.PrintInfo("No groups found")
+			return
+		}
+		existing := fetchSubscriptionFilters(ctx, client, logGroups, name)
+
+		// Setup table
+		var (
+			keyIndex       = "index"
+			keyGroup       = "group"
+			keyAction      = "action"
+			keyPattern     = "pattern"
+			keyDestination = "destination"
+			keyCurrent     = "current"
+		)
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyGroup, "Group", true),
+			tables.NewColumn(keyAction, "Action", true),
+			tables.NewColumn(keyPattern, "Pattern", true),
+			tables.NewColumn(keyDestination, "Destination", true),
+			tables.NewColumn(keyCurrent, "Current Pattern", true),
+		}
+
+		rows := []tables.Row{}
+		names := []string{}
+		for index, group := range logGroups {
+			action, current := "create", "-"
+			for _, filter := range existing[*group.LogGroupName] {
+				if aws.ToString(filter.FilterName) == name {
+					action, current = "replace", aws.ToString(filter.FilterPattern)
+				}
+			}
+			rows = append(rows, tables.Row{
+				keyIndex:       fmt.Sprintf("%d", index+1),
+				keyGroup:       *group.LogGroupName,
+				keyAction:      action,
+				keyPattern:     pattern,
+				keyDestination: destination,
+				keyCurrent:     current,
+			})
+			names = append(names, *group.LogGroupName)
+		}
+
+		prompt := fmt.Sprintf("Create subscription filter '%s' in %d groups?", name, len(names))
+		if !confirmChanges(ctx, cmd, tables.New(columns).WithRows(rows), prompt) {
+			return
+		}
+
+		applyToGroups(ctx, names, "Create subscription filter", func(group string) error {
+			groupParams := params
+			groupParams.LogGroupName = &group
+			_, err := client.PutSubscriptionFilter(ctx, &groupParams)
+			return err
+		})
+	},
+}
+
+var logsSubscriptionsTestCommand = &cobra.Command{
+	Use:   "test [group...]",
+	Short: "Test a subscription filter on recent events of log groups and show the matching events",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		name, err := cmd.Flags().GetString("name")
+		utils.CheckErr(err)
+		pattern, err := cmd.Flags().GetString("filter")
+		utils.CheckErr(err)
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroups(ctx, cmd, client, args)
+		if len(logGroups) == 0 {
+			style.PrintInfo("No groups found")
+			return
+		}
+
+		patterns := map[string]groupPattern{}
+		if name == "" {
+			checkFilterPattern(ctx, client, pattern)
+			for _, group := range logGroups {
+				patterns[*group.LogGroupName] = groupPattern{pattern: pattern}
+			}
+		} else {
+			filters := fetchSubscriptionFilters(ctx, client, logGroups, name)
+			for _, group := range logGroups {
+				for _, filter := range filters[*group.LogGroupName] {
+					if aws.ToString(filter.FilterName) == name {
+						patterns[*group.LogGroupName] = groupPattern{pattern: aws.ToString(filter.FilterPattern)}
+					}
+				}
+			}
+			if len(patterns) == 0 {
+				style.PrintInfo("No subscription filter '%s' found in %d groups", name, len(logGroups))
+				return
+			}
+		}
+
+		testGroupPatterns(ctx, cmd, client, logGroups, patterns)
+	},
+}
+
+var logsSubscriptionsDeleteCommand = &cobra.Command{
+	Use:   "delete [group...]",
+	Short: "Delete a subscription filter of log groups",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		name, err := cmd.Flags().GetString("name")
+		utils.CheckErr(err)
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroupsToChange(ctx, cmd, client, args)
+		filters := fetchSubscriptionFilters(ctx, client, logGroups, name)
+
+		// Setup table
+		var (
+			keyIndex       = "index"
+			keyGroup       = "group"
+			keyPattern     = "pattern"
+			keyDestination = "destination"
+		)
+
+		columns := []tables.Column{
+			tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+			tables.NewColumn(keyGroup, "Group", true),
+			tables.NewColumn(keyPattern, "Pattern", true),
+			tables.NewColumn(keyDestination, "Destination", true),
+		}
+
+		rows := []tables.Row{}
+		names := []string{}
+		for _, group := range logGroups {
+			for _, filter := range filters[*group.LogGroupName] {
+				if aws.ToString(filter.FilterName) != name {
+					continue
+				}
+				rows = append(rows, tables.Row{
+					keyIndex:       fmt.Sprintf("%d", len(rows)+1),
+					keyGroup:       *group.LogGroupName,
+					keyPattern:     aws.ToString(filter.FilterPattern),
+					keyDestination: aws.ToString(filter.DestinationArn),
+				})
+				names = append(names, *group.LogGroupName)
+			}
+		}
+		if len(names) == 0 {
+			style.PrintInfo("No subscription filter '%s' found in %d groups", name, len(logGroups))
+			return
+		}
+
+		prompt := fmt.Sprintf("Delete subscription filter '%s' of %d groups?", name, len(names))
+		if !confirmChanges(ctx, cmd, tables.New(columns).WithRows(rows), prompt) {
+			return
+		}
+
+		applyToGroups(ctx, names, "Delete subscription filter", func(group string) error {
+			_, err := client.DeleteSubscriptionFilter(ctx, &cloudwatchlogs.DeleteSubscriptionFilterInput{
+				LogGroupName: &group,
+				FilterName:   &name,
+			})
+			return err
+		})
+	},
+}
+
+// Parse a distribution of events to Kinesis streams, case insensitive
+func parseDistribution(value string) (types.Distribution, error) {
+	for _, d := range types.Distribution("").Values() {
+		if strings.EqualFold(string(d), value) {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("invalid distribution '%s', expected ByLogStream or Random", value)
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/ravvio/awst/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addGroupSelectionFlags(logsTagCommand)
+	addConfirmFlags(logsTagCommand)
+
+	logsTagCommand.Flags().StringArrayP("tag", "t", []string{}, "tag to add or change as key=value, can be repeated")
+	logsTagCommand.MarkFlagRequired("tag")
+
+	addGroupSelectionFlags(logsUntagCommand)
+	addConfirmFlags(logsUntagCommand)
+
+	logsUntagCommand.Flags().StringSliceP("key", "k", []string{}, "key of a tag to remove, can be repeated")
+	logsUntagCommand.MarkFlagRequired("key")
+}
+
+var logsTagCommand = &cobra.Command{
+	Use:   "tag [group...]",
+	Short: "Add or change tags of cloudwatch log groups",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		pairs, err := cmd.Flags().GetStringArray("tag")
+		utils.CheckErr(err)
+		tags, err := parseTags(pairs)
+		utils.CheckErr(err)
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroupsToChange(ctx, cmd, client, args)
+		current := fetchGroupTags(ctx, client, logGroups)
+
+		changes := map[string][]string{}
+		for _, group := range logGroups {
+			groupTags := current[*group.LogGroupName]
+			for _, key := range sortedKeys(tags) {
+				old, ok := groupTags[key]
+				if !ok {
+					changes[*group.LogGroupName] = append(changes[*group.LogGroupName], fmt.Sprintf("+%s=%s", key, tags[key]))
+				} else if old != tags[key] {
+					changes[*group.LogGroupName] = append(changes[*group.LogGroupName], fmt.Sprintf("~%s=%s→%s", key, old, tags[key]))
+				}
+			}
+		}
+
+		arns, ok := confirmTagChanges(ctx, cmd, logGroups, current, changes, "Change tags of %d groups?")
+		if !ok {
+			return
+		}
+		applyToGroups(ctx, arns.names, "Tag", func(name string) error {
+			_, err := client.TagResource(ctx, &cloudwatchlogs.TagResourceInput{
+				ResourceArn: arns.byName[name],
+				Tags:        tags,
+			})
+			return err
+		})
+	},
+}
+
+var logsUntagCommand = &cobra.Command{
+	Use:   "untag [group...]",
+	Short: "Remove tags of cloudwatch log groups",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		keys, err := cmd.Flags().GetStringSlice("key")
+		utils.CheckErr(err)
+
+		// Load config
+		cfg, err := loadAwsConfig(ctx)
+		utils.CheckErr(err)
+
+		client := cloudwatchlogs.NewFromConfig(cfg)
+
+		logGroups := selectLogGroupsToChange(ctx, cmd, client, args)
+		current := fetchGroupTags(ctx, client, logGroups)
+
+		changes := map[string][]string{}
+		for _, group := range logGroups {
+			groupTags := current[*group.LogGroupName]
+			for _, key := range keys {
+				if value, ok := groupTags[key]; ok {
+					changes[*group.LogGroupName] = append(changes[*group.LogGroupName], fmt.Sprintf("-%s=%s", key, value))
+				}
+			}
+		}
+
+		arns, ok := confirmTagChanges(ctx, cmd, logGroups, current, changes, "Remove tags of %d groups?")
+		if !ok {
+			return
+		}
+		applyToGroups(ctx, arns.names, "Untag", func(name string) error {
+			_, err := client.UntagResource(ctx, &cloudwatchlogs.UntagResourceInput{
+				ResourceArn: arns.byName[name],
+				TagKeys:     keys,
+			})
+			return err
+		})
+	},
+}
+
+// Names of log groups to change, with their ARNs used by tagging operations
+type groupArns struct {
+	names  []string
+	byName map[string]*string
+}
+
+// Print the tag changes of each group and ask for confirmation, reporting
+// false if there is nothing to change or changes are not confirmed
+func confirmTagChanges(
+	ctx context.Context,
+	cmd *cobra.Command,
+	logGroups []types.LogGroup,
+	current map[string]map[string]string,
+	changes map[string][]string,
+	prompt string,
+) (groupArns, bool) {
+	arns := groupArns{byName: map[string]*string{}}
+	for _, group := range logGroups {
+		if len(changes[*group.LogGroupName]) > 0 {
+			arns.names = append(arns.names, *group.LogGroupName)
+			arns.byName[*group.LogGroupName] = group.LogGroupArn
+		}
+	}
+	if len(arns.names) == 0 {
+		style.PrintInfo("No groups to change out of %d found", len(logGroups))
+		return arns, false
+	}
+
+	// Setup table
+	var (
+		keyIndex   = "index"
+		keyName    = "name"
+		keyChanges = "changes"
+		keyTags    = "tags"
+	)
+
+	columns := []tables.Column{
+		tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+		tables.NewColumn(keyName, "Name", true),
+		tables.NewColumn(keyChanges, "Changes", true),
+		tables.NewColumn(keyTags, "Current Tags", true),
+	}
+
+	rows := []tables.Row{}
+	for index, name := range arns.names {
+		rows = append(rows, tables.Row{
+			keyIndex:   fmt.Sprintf("%d", index+1),
+			keyName:    name,
+			keyChanges: strings.Join(changes[name], ", "),
+			keyTags:    formatTags(current[name]),
+		})
+	}
+
+	ok := confirmChanges(ctx, cmd, tables.New(columns).WithRows(rows), fmt.Sprintf(prompt, len(arns.names)))
+	return arns, ok
+}
+
+// Fetch the tags of each log group by name
+func fetchGroupTags(
+	ctx context.Context,
+	client *cloudwatchlogs.Client,
+	logGroups []types.LogGroup,
+) map[string]map[string]string {
+	tags := map[string]map[string]string{}
+	for i, group := range logGroups {
+		style.PrintProgress("Fetching tags %d/%d: %s", i+1, len(logGroups), *group.LogGroupName)
+		output, err := client.ListTagsForResource(ctx, &cloudwatchlogs.ListTagsForResourceInput{
+			ResourceArn: group.LogGroupArn,
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr)
+		}
+		checkCtxErr(ctx, err)
+		tags[*group.LogGroupName] = output.Tags
+	}
+	if len(logGroups) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	return tags
+}
+
+// Parse key=value pairs into tags
+func parseTags(pairs []string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag '%s', expected key=value", pair)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// Tags as key=value pairs sorted by key
+func formatTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return "-"
+	}
+	pairs := []string{}
+	for _, key := range sortedKeys(tags) {
+		pairs = append(pairs, key+"="+tags[key])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := parseTags([]string{"env=prod", "team=a,b", "empty=", "url=a=b"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"env":   "prod",
+		"team":  "a,b",
+		"empty": "",
+		"url":   "a=b",
+	}, tags)
+
+	for _, pair := range []string{"env", "=prod"} {
+		_, err := parseTags([]string{pair})
+		assert.Error(t, err, pair)
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	assert.Equal(t, "a=1, b=2", formatTags(map[string]string{"b": "2", "a": "1"}))
+	assert.Equal(t, "-", formatTags(nil))
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/ravvio/awst/utils"
+)
+
+// Maximum number of log groups accepted by a single live tail session
+const liveTailMaxGroups = 10
+
+// Setup live tail params with the given filter pattern and log streams
+func liveTailParams(
+	filter string,
+	streamNames []string,
+	streamPrefix *string,
+) cloudwatchlogs.StartLiveTailInput {
+	params := cloudwatchlogs.StartLiveTailInput{}
+	if filter != "" {
+		params.LogEventFilterPattern = &filter
+	}
+	params.LogStreamNames = streamNames
+	if streamPrefix != nil {
+		params.LogStreamNamePrefixes = []string{*streamPrefix}
+	}
+	return params
+}
+
+// Start live tail sessions on the given log groups and render incoming events.
+// Filter pattern and log streams selection are taken from params.
+// Events held back by the renderer, such as joined lines, are flushed once no
+// events are received for flushAfter.
+// Sessions are closed when the context is done, callers exit with
+// exitIfDone once it returns. The error of the first session failing for
+// another reason is returned.
+func liveTail(
+	ctx context.Context,
+	client fetch.LiveTailClient,
+	logGroups []types.LogGroup,
+	params cloudwatchlogs.StartLiveTailInput,
+	r tlog.Renderer,
+	flushAfter time.Duration,
+) error {
+	eventsChan := make(chan fetch.LiveTailEvent)
+	errChan := make(chan error)
+
+	// Create tail sessions
+	sessions := 0
+	i := 0
+	for {
+		n := min(i+liveTailMaxGroups, len(logGroups))
+
+		identifiers := []string{}
+		for _, l := range logGroups[i:n] {
+			identifiers = append(identifiers, *l.LogGroupArn)
+		}
+
+		tailParams := params
+		tailParams.LogGroupIdentifiers = identifiers
+
+		tail := fetch.NewLiveTail(client, tailParams)
+
+		// Log streams can be selected only on sessions with a single log
+		// group, otherwise select them on the client side
+		streamNames := params.LogStreamNames
+		streamPrefixes := params.LogStreamNamePrefixes
+		if len(identifiers) > 1 && (len(streamNames) > 0 || len(streamPrefixes) > 0) {
+			tail.Params.LogStreamNames = nil
+			tail.Params.LogStreamNamePrefixes = nil
+			tail.Filter = func(event *fetch.LiveTailEvent) bool {
+				name := aws.ToString(event.LogStreamName)
+				for _, streamName := range streamNames {
+					if name == streamName {
+						return true
+					}
+				}
+				for _, prefix := range streamPrefixes {
+					if strings.HasPrefix(name, prefix) {
+						return true
+					}
+				}
+				return false
+			}
+		}
+
+		tail.OnSessionStart = func(sessionId string) {
+			style.PrintInfo("Session %s start", sessionId)
+		}
+		tail.OnReconnect = func(err error, delay time.Duration) {
+			style.PrintInfo("Session interrupted (%s), reconnecting in %s", err.Error(), delay)
+		}
+		go func() {
+			err := tail.Run(ctx, eventsChan)
+
+			// Some patterns accepted by FilterLogEvents are rejected by
+			// live tail, in that case apply the filter on the client side
+			var invalid *types.InvalidParameterException
+			if tailParams.LogEventFilterPattern != nil && errors.As(err, &invalid) {
+				pattern, patternErr := utils.CompileFilterPattern(*tailParams.LogEventFilterPattern)
+				if patternErr != nil {
+					errChan <- fmt.Errorf("live tail rejected filter pattern: %w", err)
+					return
+				}
+
+				style.PrintWarning("Live tail rejected the filter pattern, filtering events on the client side")
+				tail.Params.LogEventFilterPattern = nil
+				streamFilter := tail.Filter
+				tail.Filter = func(event *fetch.LiveTailEvent) bool {
+					if streamFilter != nil && !streamFilter(event) {
+						return false
+					}
+					return pattern.Match(aws.ToString(event.Message))
+				}
+				err = tail.Run(ctx, eventsChan)
+			}
+			errChan <- err
+		}()
+		sessions++
+
+		i = n
+		if i >= len(logGroups) {
+			break
+		}
+	}
+
+	if flushAfter <= 0 {
+		flushAfter = tlog.DEFAULT_JOIN_WINDOW
+	}
+	flush := time.NewTicker(flushAfter)
+	defer flush.Stop()
+	received := false
+
+	for {
+		select {
+		case event := <-eventsChan:
+			log := utils.LogFromLiveTailEvent(&event)
+			r.Render(&log)
+			received = true
+		case <-flush.C:
+			if !received {
+				if err := tlog.Flush(r); err != nil {
+					return err
+				}
+			}
+			received = false
+		case err := <-errChan:
+			// Wait for all sessions to be closed once interrupted
+			if ctx.Err() != nil {
+				sessions--
+				if sessions == 0 {
+					return tlog.Flush(r)
+				}
+				continue
+			}
+			if flushErr := tlog.Flush(r); flushErr != nil {
+				return flushErr
+			}
+			return err
+		}
+	}
+}
@@ -1,21 +1,69 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/ravvio/awst/ui/style"
+	"github.com/ravvio/awst/utils"
 	"github.com/spf13/cobra"
 )
 
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first interrupt cancels the command context, letting commands
+	// flush their output and close open sessions, the second one quits
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		style.PrintInfo("Interrupted, stopping (interrupt again to force quit)")
+		cancel()
+		<-signals
+		os.Exit(utils.EXIT_INTERRUPTED)
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
 }
 
+// Exit if the command context is done, with the interrupt status or a timeout
+// error, once the output received so far was rendered
+func exitIfDone(ctx context.Context) {
+	switch ctx.Err() {
+	case context.Canceled:
+		os.Exit(utils.EXIT_INTERRUPTED)
+	case context.DeadlineExceeded:
+		utils.CheckErr(fmt.Errorf("timeout of %s exceeded", timeout))
+	}
+}
+
+// Check the error of a request made with the command context, exiting as
+// exitIfDone if the context is done
+func checkCtxErr(ctx context.Context, err error) {
+	if err != nil {
+		exitIfDone(ctx)
+	}
+	utils.CheckErr(err)
+}
+
 var (
 	region  string
 	profile string
+	timeout time.Duration
+
+	cancelTimeout context.CancelFunc = func() {}
+
+	outputFormat string
+	allColumns   bool
 )
 
 func init() {
@@ -23,6 +71,9 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&region, "region", "", "Specify AWS region")
 	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Specify AWS profile")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Specify maximum duration of the command, such as 30s or 5m")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Specify output format, tables support text, json, csv, tsv, yaml and markdown, log events support text, json, logfmt, csv and raw")
+	rootCmd.PersistentFlags().BoolVar(&allColumns, "all-columns", false, "Show all table columns in output")
 
 	s3command.AddCommand(s3listCommand)
 
@@ -30,11 +81,43 @@ func init() {
 	logsCommand.AddCommand(logsListCommad)
 	logsCommand.AddCommand(logsGetCommand)
 	logsCommand.AddCommand(logsSearchCommand)
+	logsCommand.AddCommand(logsQueryCommand)
+	logsCommand.AddCommand(logsStreamsCommand)
+	logsCommand.AddCommand(logsExportCommand)
+	logsCommand.AddCommand(logsExportS3Command)
+	logsCommand.AddCommand(logsHistogramCommand)
+	logsCommand.AddCommand(logsPatternsCommand)
+	logsCommand.AddCommand(logsCreateCommand)
+	logsCommand.AddCommand(logsDeleteCommand)
+	logsCommand.AddCommand(logsRetentionCommand)
+	logsRetentionCommand.AddCommand(logsRetentionSetCommand)
+	logsCommand.AddCommand(logsTagCommand)
+	logsCommand.AddCommand(logsUntagCommand)
+	logsCommand.AddCommand(logsMetricFiltersCommand)
+	logsMetricFiltersCommand.AddCommand(logsMetricFiltersListCommand)
+	logsMetricFiltersCommand.AddCommand(logsMetricFiltersCreateCommand)
+	logsMetricFiltersCommand.AddCommand(logsMetricFiltersTestCommand)
+	logsMetricFiltersCommand.AddCommand(logsMetricFiltersDeleteCommand)
+	logsCommand.AddCommand(logsSubscriptionsCommand)
+	logsSubscriptionsCommand.AddCommand(logsSubscriptionsListCommand)
+	logsSubscriptionsCommand.AddCommand(logsSubscriptionsCreateCommand)
+	logsSubscriptionsCommand.AddCommand(logsSubscriptionsTestCommand)
+	logsSubscriptionsCommand.AddCommand(logsSubscriptionsDeleteCommand)
 }
 
 var rootCmd = &cobra.Command{
 	Use:   "awst",
 	Short: "A utility to manage AWS resources",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cancelTimeout = cancel
+			cmd.SetContext(ctx)
+		}
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		cancelTimeout()
+	},
 }
 
 var s3command = &cobra.Command{
@@ -3,28 +3,53 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/ravvio/awst/ui/style"
 	"github.com/ravvio/awst/ui/tables"
 	"github.com/ravvio/awst/utils"
 	"github.com/spf13/cobra"
 )
 
 func init() {
-	s3listCommand.Flags().BoolP("all", "a", false, "Do not limit number of buckets to fetch")
-	s3listCommand.Flags().Int32P("limit", "l", 50, "Maximum number of buckets to fetch")
+	s3listCommand.Flags().BoolP("all", "a", false, "Do not limit number of buckets or objects to fetch")
+	s3listCommand.Flags().Int32P("limit", "l", 50, "Maximum number of buckets or objects to fetch")
 
 	s3listCommand.MarkFlagsMutuallyExclusive("all", "limit")
 }
 
 var s3listCommand = &cobra.Command{
-	Use:   "list",
-	Short: "",
+	Use:   "list [bucket[/prefix]]",
+	Short: "List buckets, or objects of a bucket under an optional prefix",
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
 		// Load config
-		cfg, err := loadAwsConfig(context.TODO())
+		cfg, err := loadAwsConfig(ctx)
 		utils.CheckErr(err)
 
+		all, err := cmd.Flags().GetBool("all")
+		utils.CheckErr(err)
+		limit, err := cmd.Flags().GetInt32("limit")
+		utils.CheckErr(err)
+		if all {
+			limit = -1
+		}
+
+		client := s3.NewFromConfig(cfg)
+
+		if len(args) > 0 {
+			bucket, prefix, _ := strings.Cut(strings.TrimPrefix(args[0], "s3://"), "/")
+			printObjects(ctx, client, bucket, prefix, limit)
+			return
+		}
+
 		// Setup params
 		params := &s3.ListBucketsInput{}
 
@@ -34,18 +59,13 @@ var s3listCommand = &cobra.Command{
 			params.BucketRegion = &region
 		}
 
-		all, err := cmd.Flags().GetBool("all")
-		utils.CheckErr(err)
 		if !all {
-			limit, err := cmd.Flags().GetInt32("limit")
-			utils.CheckErr(err)
 			params.MaxBuckets = &limit
 		}
 
 		// Request
-		client := s3.NewFromConfig(cfg)
-		output, err := client.ListBuckets(context.TODO(), params)
-		utils.CheckErr(err)
+		output, err := client.ListBuckets(ctx, params)
+		checkCtxErr(ctx, err)
 
 		// Setup table
 		var (
@@ -72,6 +92,75 @@ var s3listCommand = &cobra.Command{
 		table := tables.New(columns).WithRows(rows)
 
 		// Render table
-		fmt.Println(table.Render())
+		printTable(table)
 	},
 }
+
+// List the objects of a bucket under the given prefix, up to limit objects if it
+// is not negative, and print them as a table
+func printObjects(ctx context.Context, client *s3.Client, bucket string, prefix string, limit int32) {
+	params := s3.ListObjectsV2Input{
+		Bucket: &bucket,
+	}
+	if prefix != "" {
+		params.Prefix = &prefix
+	}
+
+	objectsFetcher := fetch.NewObjectsFetcher(
+		ctx,
+		&fetch.ObjectsFetcherClient{
+			Client: client,
+			Params: params,
+		},
+	)
+	if limit >= 0 {
+		objectsFetcher = objectsFetcher.WithLimit(limit)
+	}
+	objects, err := objectsFetcher.All()
+	checkCtxErr(ctx, err)
+
+	if len(objects) == 0 {
+		style.PrintInfo("No objects found")
+		return
+	}
+
+	// Setup table
+	var (
+		keyIndex        = "index"
+		keyKey          = "key"
+		keyLastModified = "last_modified"
+		keySize         = "size"
+		keyStorageClass = "storage_class"
+	)
+
+	columns := []tables.Column{
+		tables.NewColumn(keyIndex, "#", true).WithAlignment(tables.Right),
+		tables.NewColumn(keyKey, "Key", true),
+		tables.NewColumn(keyLastModified, "Last Modified", true),
+		tables.NewColumn(keySize, "Size", true).WithAlignment(tables.Right),
+		tables.NewColumn(keyStorageClass, "Storage Class", false),
+	}
+
+	rows := []tables.Row{}
+	for index, object := range objects {
+		rows = append(rows, tables.Row{
+			keyIndex:        fmt.Sprintf("%d", index+1),
+			keyKey:          aws.ToString(object.Key),
+			keyLastModified: formatObjectTime(object),
+			keySize:         utils.FormatBytes(aws.ToInt64(object.Size)),
+			keyStorageClass: string(object.StorageClass),
+		})
+	}
+
+	table := tables.New(columns).WithRows(rows)
+
+	// Render table
+	printTable(table)
+}
+
+func formatObjectTime(object types.Object) string {
+	if object.LastModified == nil {
+		return "-"
+	}
+	return object.LastModified.Format(time.DateTime)
+}
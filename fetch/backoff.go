@@ -0,0 +1,63 @@
+package fetch
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	DEFAULT_MIN_BACKOFF    = 1 * time.Second
+	DEFAULT_MAX_BACKOFF    = 1 * time.Minute
+	DEFAULT_BACKOFF_JITTER = 0.5
+)
+
+// Exponential backoff between a minimum and a maximum delay
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+	// Fraction of each delay which is randomly subtracted from it, so that
+	// concurrent clients do not retry all at once
+	Jitter float64
+
+	attempt int
+}
+
+func NewBackoff() Backoff {
+	return Backoff{
+		Min:    DEFAULT_MIN_BACKOFF,
+		Max:    DEFAULT_MAX_BACKOFF,
+		Jitter: DEFAULT_BACKOFF_JITTER,
+	}
+}
+
+// Delay to wait before the next attempt
+func (b *Backoff) Next() time.Duration {
+	delay := b.Min << b.attempt
+	if delay <= 0 || delay > b.Max {
+		delay = b.Max
+	} else {
+		b.attempt++
+	}
+	if b.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * b.Jitter * float64(delay))
+	}
+	return delay
+}
+
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// Wait for the given delay, returning early with an error if the context is done
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
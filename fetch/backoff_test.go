@@ -0,0 +1,44 @@
+package fetch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ravvio/awst/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	b := fetch.Backoff{Min: 100 * time.Millisecond, Max: time.Second}
+
+	delays := []time.Duration{}
+	for i := 0; i < 6; i++ {
+		delays = append(delays, b.Next())
+	}
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}, delays)
+
+	// Delays stay capped however many attempts are made
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, time.Second, b.Next())
+	}
+
+	b.Reset()
+	assert.Equal(t, 100*time.Millisecond, b.Next())
+}
+
+func TestBackoffJitter(t *testing.T) {
+	b := fetch.Backoff{Min: 100 * time.Millisecond, Max: time.Second, Jitter: 0.5}
+	for _, full := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		full *= time.Millisecond
+		delay := b.Next()
+		assert.LessOrEqual(t, delay, full)
+		assert.GreaterOrEqual(t, delay, full/2)
+	}
+}
@@ -0,0 +1,84 @@
+package fetch
+
+// Inclusive range of unix milliseconds
+type TimeRange struct {
+	Start int64
+	End   int64
+}
+
+// Split the inclusive time range [start, end] in at most n contiguous slices
+// of about the same duration
+func SplitTimeRange(start, end int64, n int) []TimeRange {
+	n = max(n, 1)
+	span := end - start + 1
+	if span < int64(n) {
+		n = int(max(span, 1))
+	}
+
+	slices := make([]TimeRange, 0, n)
+	for i := 0; i < n; i++ {
+		slices = append(slices, TimeRange{
+			Start: start + span*int64(i)/int64(n),
+			End:   start + span*int64(i+1)/int64(n) - 1,
+		})
+	}
+	return slices
+}
+
+// Concatenate the items of fetchers, calling emit for the items of each fetcher
+// after all the items of the previous ones, stopping after limit items if it
+// is not negative.
+// Pages of all fetchers are fetched concurrently, with at most maxPar requests
+// at a time, and each fetcher holds at most one page ahead of the
+// concatenation. Fetching stops once limit items are emitted.
+// If a fetcher fails, the items of the previous ones are emitted and its error
+// is returned as a TruncatedError. Concatenation stops at the first error
+// returned by emit.
+func Concat[C FetcherClient[T], T any](
+	fetchers []Fetcher[C, T],
+	maxPar int,
+	limit int32,
+	emit func(item T) error,
+) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	semaphore := make(chan struct{}, max(maxPar, 1))
+
+	sources := make([]<-chan Page[T], len(fetchers))
+	for i := range fetchers {
+		if limit >= 0 {
+			fetchers[i] = fetchers[i].WithLimit(limit)
+		}
+		pages := make(chan Page[T], 1)
+		sources[i] = pages
+		go fetchPages(&fetchers[i], semaphore, pages, done)
+	}
+
+	if limit == 0 {
+		return nil
+	}
+
+	emitted := 0
+	for _, pages := range sources {
+		for p := range pages {
+			if p.Err != nil {
+				if emitted == 0 {
+					return p.Err
+				}
+				return &TruncatedError{Fetched: emitted, Err: p.Err}
+			}
+			for _, item := range p.Items {
+				if err := emit(item); err != nil {
+					return err
+				}
+				emitted++
+				// Return without waiting for further pages
+				if limit >= 0 && emitted >= int(limit) {
+					return nil
+				}
+			}
+		}
+	}
+	return nil
+}
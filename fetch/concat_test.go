@@ -0,0 +1,96 @@
+package fetch_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ravvio/awst/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTimeRange(t *testing.T) {
+	assert.Equal(
+		t,
+		[]fetch.TimeRange{{Start: 0, End: 32}, {Start: 33, End: 65}, {Start: 66, End: 99}},
+		fetch.SplitTimeRange(0, 99, 3),
+	)
+	assert.Equal(
+		t,
+		[]fetch.TimeRange{{Start: 10, End: 10}, {Start: 11, End: 11}},
+		fetch.SplitTimeRange(10, 11, 4),
+	)
+	assert.Equal(t, []fetch.TimeRange{{Start: 5, End: 9}}, fetch.SplitTimeRange(5, 9, 0))
+}
+
+func TestConcat(t *testing.T) {
+	fetchers := func() []SliceFetcher {
+		return []SliceFetcher{
+			sliceFetcher([]int{1, 2, 3}, 2),
+			sliceFetcher([]int{}, 2),
+			sliceFetcher([]int{4, 5, 6, 7}, 1),
+			sliceFetcher([]int{8, 9}, 3),
+		}
+	}
+
+	items := []int{}
+	err := fetch.Concat(fetchers(), 2, -1, func(item int) error {
+		items = append(items, item)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, items)
+
+	// Limit applies across fetchers
+	items = []int{}
+	err = fetch.Concat(fetchers(), 3, 5, func(item int) error {
+		items = append(items, item)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, items)
+}
+
+func TestConcatFailure(t *testing.T) {
+	fetchers := []SliceFetcher{
+		sliceFetcher([]int{1, 2, 3}, 2),
+		fetch.NewFetcher(context.Background(), &SliceFetcherClient{items: []int{4, 5, 6}, failAt: 2}, 2),
+		sliceFetcher([]int{7, 8}, 2),
+	}
+
+	items := []int{}
+	err := fetch.Concat(fetchers, 3, -1, func(item int) error {
+		items = append(items, item)
+		return nil
+	})
+
+	// Items after the failed page are not emitted
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, items)
+	var truncated *fetch.TruncatedError
+	assert.ErrorAs(t, err, &truncated)
+	assert.Equal(t, 5, truncated.Fetched)
+}
+
+func TestConcatBounded(t *testing.T) {
+	items := make([]int, 100)
+	clients := []*SliceFetcherClient{}
+	fetchers := []SliceFetcher{}
+	for i := 0; i < 3; i++ {
+		c := &SliceFetcherClient{items: items}
+		clients = append(clients, c)
+		fetchers = append(fetchers, fetch.NewFetcher(context.Background(), c, 1))
+	}
+
+	emitted := 0
+	err := fetch.Concat(fetchers, 3, 5, func(item int) error {
+		emitted++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, emitted)
+
+	// Later fetchers hold at most a page ahead, plus the one waiting to be
+	// sent, instead of fetching all their pages
+	for _, c := range clients[1:] {
+		assert.LessOrEqual(t, c.page, 2)
+	}
+}
@@ -0,0 +1,116 @@
+package fetch
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const (
+	// Events requested in addition to the context ones, to find the event
+	// among the others with the same timestamp
+	contextSlack = 50
+	// Pages requested at most for each side of the context, pages may be
+	// empty before the end of the stream
+	contextMaxPages = 10
+)
+
+// Fetch up to before and after events of a stream around an event, which is
+// identified by its timestamp and message
+func FetchContext(
+	ctx context.Context,
+	client StreamEventsGetter,
+	group string,
+	stream string,
+	timestamp int64,
+	message string,
+	before int,
+	after int,
+) ([]types.OutputLogEvent, []types.OutputLogEvent, error) {
+	var beforeEvents, afterEvents []types.OutputLogEvent
+
+	if before > 0 {
+		// Latest events up to the event timestamp
+		events, err := fetchStreamEvents(ctx, client, cloudwatchlogs.GetLogEventsInput{
+			LogGroupName:  &group,
+			LogStreamName: &stream,
+			EndTime:       aws.Int64(timestamp + 1),
+			StartFromHead: aws.Bool(false),
+		}, before+contextSlack)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		end := len(events)
+		for i := len(events) - 1; i >= 0; i-- {
+			if isEvent(events[i], timestamp, message) {
+				end = i
+				break
+			}
+		}
+		beforeEvents = events[max(end-before, 0):end]
+	}
+
+	if after > 0 {
+		// Earliest events from the event timestamp
+		events, err := fetchStreamEvents(ctx, client, cloudwatchlogs.GetLogEventsInput{
+			LogGroupName:  &group,
+			LogStreamName: &stream,
+			StartTime:     &timestamp,
+			StartFromHead: aws.Bool(true),
+		}, after+contextSlack)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		start := 0
+		for i := range events {
+			if isEvent(events[i], timestamp, message) {
+				start = i + 1
+				break
+			}
+		}
+		afterEvents = events[start:min(start+after, len(events))]
+	}
+
+	return beforeEvents, afterEvents, nil
+}
+
+// Fetch up to limit events of a stream in chronological order, following
+// pages backward from the end unless starting from the head
+func fetchStreamEvents(
+	ctx context.Context,
+	client StreamEventsGetter,
+	params cloudwatchlogs.GetLogEventsInput,
+	limit int,
+) ([]types.OutputLogEvent, error) {
+	params.Limit = aws.Int32(int32(limit))
+	eventsFetcher := NewStreamEventsFetcher(
+		ctx,
+		&StreamEventsFetcherClient{
+			Client: client,
+			Params: params,
+		},
+	).WithLimit(int32(limit))
+
+	events := []types.OutputLogEvent{}
+	for pages := 0; eventsFetcher.HasNextPage() && pages < contextMaxPages; pages++ {
+		page, err := eventsFetcher.NextPage()
+		if err != nil {
+			return nil, err
+		}
+		if aws.ToBool(params.StartFromHead) {
+			events = append(events, page...)
+		} else {
+			events = slices.Concat(page, events)
+		}
+	}
+	return events, nil
+}
+
+func isEvent(event types.OutputLogEvent, timestamp int64, message string) bool {
+	return aws.ToInt64(event.Timestamp) == timestamp && aws.ToString(event.Message) == message
+}
@@ -0,0 +1,170 @@
+package fetch_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+// Stream of events paginated as GetLogEvents does, tokens hold the position
+// of the next page and its direction. The first empty pages are returned
+// without events, as CloudWatch does when scanning sparse streams.
+type fakeStream struct {
+	events     []types.OutputLogEvent
+	emptyPages int
+	requests   int
+}
+
+func newFakeStream(events ...string) *fakeStream {
+	s := &fakeStream{}
+	for _, e := range events {
+		timestamp, message, _ := strings.Cut(e, " ")
+		ts, _ := strconv.ParseInt(timestamp, 10, 64)
+		s.events = append(s.events, types.OutputLogEvent{
+			Timestamp: aws.Int64(ts),
+			Message:   aws.String(message),
+		})
+	}
+	return s
+}
+
+func (s *fakeStream) GetLogEvents(
+	ctx context.Context,
+	params *cloudwatchlogs.GetLogEventsInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	s.requests++
+
+	events := []types.OutputLogEvent{}
+	for _, e := range s.events {
+		if params.StartTime != nil && *e.Timestamp < *params.StartTime {
+			continue
+		}
+		if params.EndTime != nil && *e.Timestamp >= *params.EndTime {
+			continue
+		}
+		events = append(events, e)
+	}
+
+	forward := aws.ToBool(params.StartFromHead)
+	pos := 0
+	if !forward {
+		pos = len(events)
+	}
+	if params.NextToken != nil {
+		token, _, _ := strings.Cut(*params.NextToken, "#")
+		forward = token[0] == 'f'
+		pos, _ = strconv.Atoi(token[1:])
+	}
+
+	if s.emptyPages > 0 {
+		s.emptyPages--
+		token := fmt.Sprintf("b%d#%d", pos, s.emptyPages)
+		if forward {
+			token = fmt.Sprintf("f%d#%d", pos, s.emptyPages)
+		}
+		return &cloudwatchlogs.GetLogEventsOutput{NextForwardToken: &token, NextBackwardToken: &token}, nil
+	}
+
+	limit := int(aws.ToInt32(params.Limit))
+	start, end := pos, min(pos+limit, len(events))
+	if !forward {
+		start, end = max(pos-limit, 0), pos
+	}
+	return &cloudwatchlogs.GetLogEventsOutput{
+		Events:            events[start:end],
+		NextForwardToken:  aws.String(fmt.Sprintf("f%d", end)),
+		NextBackwardToken: aws.String(fmt.Sprintf("b%d", start)),
+	}, nil
+}
+
+func contextMessages(events []types.OutputLogEvent) []string {
+	messages := []string{}
+	for _, e := range events {
+		messages = append(messages, *e.Message)
+	}
+	return messages
+}
+
+func TestFetchContext(t *testing.T) {
+	stream := newFakeStream("1 a", "2 b", "3 c", "4 d", "5 e", "6 f")
+
+	before, after, err := fetch.FetchContext(context.Background(), stream, "g", "s", 3, "c", 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, contextMessages(before))
+	assert.Equal(t, []string{"d", "e"}, contextMessages(after))
+
+	// Context is cut at the edges of the stream
+	before, after, err = fetch.FetchContext(context.Background(), stream, "g", "s", 1, "a", 3, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, before)
+	assert.Empty(t, after)
+
+	before, after, err = fetch.FetchContext(context.Background(), stream, "g", "s", 6, "f", 0, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, before)
+	assert.Empty(t, after)
+}
+
+func TestFetchContextSameTimestamp(t *testing.T) {
+	stream := newFakeStream("1 a", "2 b", "2 c", "2 d", "3 e")
+
+	// Events with the same timestamp are split around the matching one
+	before, after, err := fetch.FetchContext(context.Background(), stream, "g", "s", 2, "c", 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, contextMessages(before))
+	assert.Equal(t, []string{"d", "e"}, contextMessages(after))
+}
+
+func TestFetchContextEmptyPages(t *testing.T) {
+	stream := newFakeStream("1 a", "2 b", "3 c", "4 d", "5 e")
+	stream.emptyPages = 2
+
+	before, _, err := fetch.FetchContext(context.Background(), stream, "g", "s", 4, "d", 2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, contextMessages(before))
+
+	stream.emptyPages = 2
+	_, after, err := fetch.FetchContext(context.Background(), stream, "g", "s", 2, "b", 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, contextMessages(after))
+}
+
+func TestFetchContextPages(t *testing.T) {
+	// More events than fit in a page, pages are followed backward and kept
+	// in chronological order
+	messages := []string{}
+	for i := 0; i < 120; i++ {
+		messages = append(messages, fmt.Sprintf("%d m%d", i, i))
+	}
+	stream := newFakeStream(messages...)
+
+	client := &fetch.StreamEventsFetcherClient{
+		Client: stream,
+		Params: cloudwatchlogs.GetLogEventsInput{
+			EndTime:       aws.Int64(100),
+			Limit:         aws.Int32(30),
+			StartFromHead: aws.Bool(false),
+		},
+	}
+	eventsFetcher := fetch.NewStreamEventsFetcher(context.Background(), client)
+	first, err := eventsFetcher.NextPage()
+	assert.NoError(t, err)
+	assert.Equal(t, "m70", *first[0].Message)
+	second, err := eventsFetcher.NextPage()
+	assert.NoError(t, err)
+	assert.Equal(t, "m40", *second[0].Message)
+
+	events, err := eventsFetcher.All()
+	assert.NoError(t, err)
+	assert.Len(t, events, 40)
+	assert.False(t, eventsFetcher.HasNextPage())
+}
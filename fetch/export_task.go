@@ -0,0 +1,138 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const DEFAULT_EXPORT_POLL_INTERVAL = 5 * time.Second
+
+// Client able to run export tasks, such as *cloudwatchlogs.Client
+type ExportTaskClient interface {
+	CreateExportTask(
+		ctx context.Context,
+		params *cloudwatchlogs.CreateExportTaskInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.CreateExportTaskOutput, error)
+	DescribeExportTasks(
+		ctx context.Context,
+		params *cloudwatchlogs.DescribeExportTasksInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.DescribeExportTasksOutput, error)
+	CancelExportTask(
+		ctx context.Context,
+		params *cloudwatchlogs.CancelExportTaskInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.CancelExportTaskOutput, error)
+}
+
+// Export of log events to S3, started with CreateExportTask and polled with
+// DescribeExportTasks until it is done.
+// Only one export task can be active in an account, while another one is
+// active the task is created again after a backoff delay.
+type ExportTask struct {
+	Client ExportTaskClient
+	Params cloudwatchlogs.CreateExportTaskInput
+
+	PollInterval time.Duration
+	Backoff      Backoff
+
+	OnQueued   func(delay time.Duration)
+	OnProgress func(task *types.ExportTask)
+}
+
+func NewExportTask(
+	client ExportTaskClient,
+	params cloudwatchlogs.CreateExportTaskInput,
+) *ExportTask {
+	return &ExportTask{
+		Client:       client,
+		Params:       params,
+		PollInterval: DEFAULT_EXPORT_POLL_INTERVAL,
+		Backoff:      NewBackoff(),
+	}
+}
+
+// Run the export task and wait for it to complete.
+// The task is cancelled if polling fails or the context is done before it
+// ends, so that it does not hold the export task slot of the account.
+func (t *ExportTask) Run(ctx context.Context) (*types.ExportTask, error) {
+	taskId, err := t.create(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ended := false
+	defer func() {
+		if !ended {
+			t.Client.CancelExportTask(context.Background(), &cloudwatchlogs.CancelExportTaskInput{
+				TaskId: taskId,
+			})
+		}
+	}()
+
+	for {
+		if err := sleep(ctx, t.PollInterval); err != nil {
+			return nil, err
+		}
+
+		res, err := t.Client.DescribeExportTasks(ctx, &cloudwatchlogs.DescribeExportTasksInput{
+			TaskId: taskId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(res.ExportTasks) == 0 {
+			return nil, fmt.Errorf("export task %s not found", aws.ToString(taskId))
+		}
+
+		task := &res.ExportTasks[0]
+		if t.OnProgress != nil {
+			t.OnProgress(task)
+		}
+
+		var status types.ExportTaskStatus
+		if task.Status != nil {
+			status = *task.Status
+		}
+		switch status.Code {
+		case types.ExportTaskStatusCodePending, types.ExportTaskStatusCodeRunning:
+			continue
+		case types.ExportTaskStatusCodeCompleted:
+			ended = true
+			return task, nil
+		default:
+			ended = true
+			return task, fmt.Errorf("export task %s: %s", status.Code, aws.ToString(status.Message))
+		}
+	}
+}
+
+func (t *ExportTask) create(ctx context.Context) (*string, error) {
+	backoff := t.Backoff
+	for {
+		res, err := t.Client.CreateExportTask(ctx, &t.Params)
+
+		var limitExceeded *types.LimitExceededException
+		if !errors.As(err, &limitExceeded) {
+			if err != nil {
+				return nil, err
+			}
+			return res.TaskId, nil
+		}
+
+		delay := backoff.Next()
+		if t.OnQueued != nil {
+			t.OnQueued(delay)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
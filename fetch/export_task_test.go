@@ -0,0 +1,101 @@
+package fetch_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+// Export task client describing the task with the given statuses in order,
+// the last one is returned again once the others are over. The task is not
+// found if there are no statuses.
+type fakeExportClient struct {
+	statuses  []types.ExportTaskStatusCode
+	err       error
+	cancelled int
+}
+
+func (c *fakeExportClient) CreateExportTask(
+	ctx context.Context,
+	params *cloudwatchlogs.CreateExportTaskInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.CreateExportTaskOutput, error) {
+	return &cloudwatchlogs.CreateExportTaskOutput{TaskId: aws.String("task")}, nil
+}
+
+func (c *fakeExportClient) DescribeExportTasks(
+	ctx context.Context,
+	params *cloudwatchlogs.DescribeExportTasksInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.DescribeExportTasksOutput, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if len(c.statuses) == 0 {
+		return &cloudwatchlogs.DescribeExportTasksOutput{}, nil
+	}
+	code := c.statuses[0]
+	if len(c.statuses) > 1 {
+		c.statuses = c.statuses[1:]
+	}
+	return &cloudwatchlogs.DescribeExportTasksOutput{
+		ExportTasks: []types.ExportTask{{TaskId: params.TaskId, Status: &types.ExportTaskStatus{Code: code}}},
+	}, nil
+}
+
+func (c *fakeExportClient) CancelExportTask(
+	ctx context.Context,
+	params *cloudwatchlogs.CancelExportTaskInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.CancelExportTaskOutput, error) {
+	c.cancelled++
+	return &cloudwatchlogs.CancelExportTaskOutput{}, nil
+}
+
+func runExportTask(ctx context.Context, client *fakeExportClient) error {
+	task := fetch.NewExportTask(client, cloudwatchlogs.CreateExportTaskInput{})
+	task.PollInterval = time.Millisecond
+	_, err := task.Run(ctx)
+	return err
+}
+
+func TestExportTask(t *testing.T) {
+	client := &fakeExportClient{statuses: []types.ExportTaskStatusCode{
+		types.ExportTaskStatusCodePending,
+		types.ExportTaskStatusCodeRunning,
+		types.ExportTaskStatusCodeCompleted,
+	}}
+	assert.NoError(t, runExportTask(context.Background(), client))
+	assert.Equal(t, 0, client.cancelled)
+
+	// Tasks which ended on their own are not cancelled
+	client = &fakeExportClient{statuses: []types.ExportTaskStatusCode{types.ExportTaskStatusCodeFailed}}
+	assert.Error(t, runExportTask(context.Background(), client))
+	assert.Equal(t, 0, client.cancelled)
+}
+
+func TestExportTaskCancel(t *testing.T) {
+	// Polling failures
+	client := &fakeExportClient{err: errors.New("polling failed")}
+	assert.Error(t, runExportTask(context.Background(), client))
+	assert.Equal(t, 1, client.cancelled)
+
+	// Task not found
+	client = &fakeExportClient{}
+	assert.Error(t, runExportTask(context.Background(), client))
+	assert.Equal(t, 1, client.cancelled)
+
+	// Context done while the task is running
+	client = &fakeExportClient{statuses: []types.ExportTaskStatusCode{types.ExportTaskStatusCodeRunning}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, runExportTask(ctx, client), context.DeadlineExceeded)
+	assert.Equal(t, 1, client.cancelled)
+}
@@ -3,6 +3,16 @@ package fetch
 import (
 	"context"
 	"fmt"
+	"iter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+const (
+	DEFAULT_FETCH_RETRIES     = 5
+	DEFAULT_FETCH_MIN_BACKOFF = 200 * time.Millisecond
+	DEFAULT_FETCH_MAX_BACKOFF = 10 * time.Second
 )
 
 type FetchData[T any] struct {
@@ -10,6 +20,12 @@ type FetchData[T any] struct {
 	NextToken *string
 }
 
+// Page of items, or the error which stopped fetching
+type Page[T any] struct {
+	Items []T
+	Err   error
+}
+
 type FetcherClient[T any] interface {
 	Fetch(context.Context) (FetchData[T], error)
 	RequestLimit() *int32
@@ -17,6 +33,36 @@ type FetcherClient[T any] interface {
 	SetNextToken(*string)
 }
 
+// Error returned with the items fetched before a page request failed
+type TruncatedError struct {
+	Fetched int
+	Err     error
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("results truncated after %d items: %s", e.Fetched, e.Err)
+}
+
+func (e *TruncatedError) Unwrap() error {
+	return e.Err
+}
+
+// Checks on top of the ones of the SDK, which already retries requests a few
+// times before returning their error
+var retryables = retry.IsErrorRetryables(append(
+	[]retry.IsErrorRetryable{
+		retry.RetryableErrorCode{Codes: map[string]struct{}{
+			"ServiceUnavailableException": {},
+		}},
+	},
+	retry.DefaultRetryables...,
+))
+
+// Whether the request failed because of throttling or a transient error
+func IsRetryable(err error) bool {
+	return retryables.IsErrorRetryable(err).Bool()
+}
+
 type Fetcher[C FetcherClient[T], T any] struct {
 	ctx    context.Context
 	client C
@@ -25,6 +71,9 @@ type Fetcher[C FetcherClient[T], T any] struct {
 	fetched    int32
 	first_page bool
 	next_token *string
+
+	retries int
+	backoff Backoff
 }
 
 func NewFetcher[C FetcherClient[T], T any](
@@ -40,6 +89,13 @@ func NewFetcher[C FetcherClient[T], T any](
 		fetched:    0,
 		first_page: true,
 		next_token: nil,
+
+		retries: DEFAULT_FETCH_RETRIES,
+		backoff: Backoff{
+			Min:    DEFAULT_FETCH_MIN_BACKOFF,
+			Max:    DEFAULT_FETCH_MAX_BACKOFF,
+			Jitter: DEFAULT_BACKOFF_JITTER,
+		},
 	}
 
 	if f.client.RequestLimit() == nil {
@@ -53,12 +109,39 @@ func (f Fetcher[C, T]) WithLimit(limit int32) Fetcher[C, T] {
 	return f
 }
 
+// Retry failed page requests up to the given number of times, waiting
+// according to backoff, if they failed because of throttling or a transient error
+func (f Fetcher[C, T]) WithRetry(retries int, backoff Backoff) Fetcher[C, T] {
+	f.retries = retries
+	f.backoff = backoff
+	return f
+}
+
+// Continue fetching from the page of the given token, returned by NextToken
+// on a previous fetcher with the same parameters
+func (f Fetcher[C, T]) WithNextToken(token *string) Fetcher[C, T] {
+	f.next_token = token
+	f.first_page = token == nil
+	return f
+}
+
+// Token of the next page, nil if there are no more pages
+func (f *Fetcher[C, T]) NextToken() *string {
+	return f.next_token
+}
+
 func (f *Fetcher[C, T]) HasNextPage() bool {
 	return f.first_page ||
 		(f.next_token != nil && (f.limit < 0 || f.fetched < f.limit))
 }
 
 func (f *Fetcher[C, T]) NextPage() ([]T, error) {
+	return f.nextPage(f.ctx)
+}
+
+// Fetch the next page with the given context, which bounds both the request
+// and the waits between retries
+func (f *Fetcher[C, T]) nextPage(ctx context.Context) ([]T, error) {
 	if !f.HasNextPage() {
 		return nil, fmt.Errorf("no next page")
 	}
@@ -72,7 +155,7 @@ func (f *Fetcher[C, T]) NextPage() ([]T, error) {
 		f.client.SetRequestLimit(&newLimit)
 	}
 
-	res, err := f.client.Fetch(f.ctx)
+	res, err := f.fetch(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -83,24 +166,89 @@ func (f *Fetcher[C, T]) NextPage() ([]T, error) {
 	return res.Data, nil
 }
 
-func (f *Fetcher[C, T]) All() ([]T, error) {
-	res, err := f.NextPage()
-	if err != nil {
-		return nil, err
+func (f *Fetcher[C, T]) fetch(ctx context.Context) (FetchData[T], error) {
+	backoff := f.backoff
+	for attempt := 0; ; attempt++ {
+		res, err := f.client.Fetch(ctx)
+		if err == nil || ctx.Err() != nil || !IsRetryable(err) {
+			return res, err
+		}
+		if attempt >= f.retries {
+			return res, fmt.Errorf("giving up after %d attempts: %w", attempt+1, err)
+		}
+
+		if err := sleep(ctx, backoff.Next()); err != nil {
+			return res, err
+		}
 	}
+}
 
-	for {
+// Fetch all remaining pages.
+// If a page request fails after the first page, the items fetched so far are
+// returned along with a TruncatedError.
+func (f *Fetcher[C, T]) All() ([]T, error) {
+	res := []T{}
+	for f.HasNextPage() {
 		r, err := f.NextPage()
 		if err != nil {
-			break
+			if f.first_page {
+				return nil, err
+			}
+			return res, &TruncatedError{Fetched: len(res), Err: err}
 		}
-
 		res = append(res, r...)
+	}
+	return res, nil
+}
 
-		if !f.HasNextPage() {
-			break
+// Iterate over the items of the remaining pages, requesting each page only once
+// the items of the previous one were consumed, so that stopping the iteration
+// does not fetch further pages.
+// Iteration ends after yielding an error, which is a TruncatedError if items
+// were yielded before it.
+func (f *Fetcher[C, T]) Items() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		yielded := 0
+		for f.HasNextPage() {
+			r, err := f.NextPage()
+			if err != nil {
+				if yielded > 0 {
+					err = &TruncatedError{Fetched: yielded, Err: err}
+				}
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range r {
+				if !yield(item, nil) {
+					return
+				}
+				yielded++
+			}
 		}
 	}
+}
 
-	return res, nil
+// Fetch the remaining pages in background, sending them to the returned channel
+// while the previous one is being consumed.
+// The channel is closed once pages are over, after a page with an error, or
+// when the given context is done, which lets callers stop early. Pages are
+// requested with the given context instead of the one of the fetcher.
+func (f *Fetcher[C, T]) Pages(ctx context.Context) <-chan Page[T] {
+	pages := make(chan Page[T], 1)
+	go func() {
+		defer close(pages)
+		for f.HasNextPage() && ctx.Err() == nil {
+			r, err := f.nextPage(ctx)
+			select {
+			case pages <- Page[T]{Items: r, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return pages
 }
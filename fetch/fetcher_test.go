@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/aws/smithy-go"
 	"github.com/ravvio/awst/fetch"
 	"github.com/stretchr/testify/assert"
 )
@@ -77,3 +79,148 @@ func TestPagination(t *testing.T) {
 
 	assert.Equal(t, false, f.HasNextPage())
 }
+
+func TestFetchRetry(t *testing.T) {
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException"}
+	backoff := fetch.Backoff{Min: time.Millisecond, Max: time.Millisecond}
+
+	c := SliceFetcherClient{items: []int{1, 2, 3, 4, 5}, failAt: 2, failures: 2, err: throttled}
+	f := fetch.NewFetcher(context.Background(), &c, 2).WithRetry(2, backoff)
+
+	r, e := f.All()
+	assert.NoError(t, e)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, r)
+
+	// Retries exhausted
+	c = SliceFetcherClient{items: []int{1, 2, 3, 4, 5}, failAt: 2, failures: 3, err: throttled}
+	f = fetch.NewFetcher(context.Background(), &c, 2).WithRetry(2, backoff)
+
+	r, e = f.All()
+	assert.Equal(t, []int{1, 2}, r)
+	assert.ErrorIs(t, e, throttled)
+}
+
+func TestFetchTruncated(t *testing.T) {
+	c := SliceFetcherClient{items: []int{1, 2, 3, 4, 5}, failAt: 3}
+	f := fetch.NewFetcher(context.Background(), &c, 2)
+
+	r, e := f.All()
+	assert.Equal(t, []int{1, 2, 3, 4}, r)
+
+	var truncated *fetch.TruncatedError
+	assert.ErrorAs(t, e, &truncated)
+	assert.Equal(t, 4, truncated.Fetched)
+	// Errors which are not transient are not retried
+	assert.Equal(t, 3, c.page)
+
+	// Nothing fetched
+	c = SliceFetcherClient{items: []int{1, 2, 3}, failAt: 1}
+	f = fetch.NewFetcher(context.Background(), &c, 2)
+
+	r, e = f.All()
+	assert.Nil(t, r)
+	assert.Error(t, e)
+	assert.NotErrorAs(t, e, &truncated)
+}
+
+func TestItems(t *testing.T) {
+	c := SliceFetcherClient{items: []int{1, 2, 3, 4, 5}}
+	f := fetch.NewFetcher(context.Background(), &c, 2)
+
+	items := []int{}
+	for item, err := range f.Items() {
+		assert.NoError(t, err)
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, items)
+
+	// Stopping early does not fetch further pages
+	c = SliceFetcherClient{items: []int{1, 2, 3, 4, 5}}
+	f = fetch.NewFetcher(context.Background(), &c, 2)
+
+	for item := range f.Items() {
+		if item == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 2, c.page)
+
+	// Errors end the iteration
+	c = SliceFetcherClient{items: []int{1, 2, 3, 4, 5}, failAt: 2}
+	f = fetch.NewFetcher(context.Background(), &c, 2)
+
+	items = []int{}
+	var truncated *fetch.TruncatedError
+	for item, err := range f.Items() {
+		if err != nil {
+			assert.ErrorAs(t, err, &truncated)
+			continue
+		}
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{1, 2}, items)
+	assert.Equal(t, 2, truncated.Fetched)
+}
+
+func TestPages(t *testing.T) {
+	c := SliceFetcherClient{items: []int{1, 2, 3, 4, 5}}
+	f := fetch.NewFetcher(context.Background(), &c, 2)
+
+	pages := [][]int{}
+	for page := range f.Pages(context.Background()) {
+		assert.NoError(t, page.Err)
+		pages = append(pages, page.Items)
+	}
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, pages)
+
+	// Stopping early closes the channel without fetching further pages
+	c = SliceFetcherClient{items: []int{1, 2, 3, 4, 5}}
+	f = fetch.NewFetcher(context.Background(), &c, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := f.Pages(ctx)
+	page := <-ch
+	assert.Equal(t, []int{1}, page.Items)
+	cancel()
+	for range ch {
+	}
+	// Pages fetched ahead of the consumer
+	assert.LessOrEqual(t, c.page, 3)
+}
+
+func TestPagesContext(t *testing.T) {
+	// Waits between retries end with the context given to Pages
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException"}
+	c := SliceFetcherClient{items: []int{1, 2, 3}, failAt: 1, failures: 5, err: throttled}
+	f := fetch.NewFetcher(context.Background(), &c, 2).
+		WithRetry(5, fetch.Backoff{Min: time.Hour, Max: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	for page := range f.Pages(ctx) {
+		assert.Error(t, page.Err)
+	}
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.Equal(t, 1, c.page)
+}
+
+func TestNextToken(t *testing.T) {
+	c := SliceFetcherClient{items: []int{1, 2, 3, 4, 5}}
+	f := fetch.NewFetcher(context.Background(), &c, 2)
+
+	_, e := f.NextPage()
+	assert.NoError(t, e)
+	token := f.NextToken()
+	assert.Equal(t, "2", *token)
+
+	// Resume from the token with a new client
+	c = SliceFetcherClient{items: []int{1, 2, 3, 4, 5}}
+	f = fetch.NewFetcher(context.Background(), &c, 2).WithNextToken(token)
+
+	r, e := f.All()
+	assert.NoError(t, e)
+	assert.Equal(t, []int{3, 4, 5}, r)
+	assert.Nil(t, f.NextToken())
+}
@@ -0,0 +1,64 @@
+package fetch
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// Maximum number of messages of a TestMetricFilter request
+const TEST_PATTERN_MAX_MESSAGES = 50
+
+// Client able to test filter patterns, such as *cloudwatchlogs.Client
+type PatternTester interface {
+	TestMetricFilter(
+		ctx context.Context,
+		params *cloudwatchlogs.TestMetricFilterInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.TestMetricFilterOutput, error)
+}
+
+// Test a filter pattern against messages, returning the values extracted from
+// each matching message by its index. Syntax errors of the pattern are
+// reported by the first request.
+func MatchFilterPattern(
+	ctx context.Context,
+	client PatternTester,
+	pattern string,
+	messages []string,
+) (map[int]map[string]string, error) {
+	matches := map[int]map[string]string{}
+
+	for start := 0; start < len(messages); start += TEST_PATTERN_MAX_MESSAGES {
+		batch := messages[start:min(start+TEST_PATTERN_MAX_MESSAGES, len(messages))]
+
+		res, err := client.TestMetricFilter(ctx, &cloudwatchlogs.TestMetricFilterInput{
+			FilterPattern:    &pattern,
+			LogEventMessages: batch,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Matches are identified by message, equal messages are matched in
+		// order of appearance
+		indexes := map[string][]int{}
+		for i, message := range batch {
+			indexes[message] = append(indexes[message], start+i)
+		}
+		for _, match := range res.Matches {
+			if match.EventMessage == nil || len(indexes[*match.EventMessage]) == 0 {
+				continue
+			}
+			i := indexes[*match.EventMessage][0]
+			indexes[*match.EventMessage] = indexes[*match.EventMessage][1:]
+
+			extracted := match.ExtractedValues
+			if extracted == nil {
+				extracted = map[string]string{}
+			}
+			matches[i] = extracted
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,65 @@
+package fetch_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tester matching messages containing the pattern, extracting their length
+type containsTester struct {
+	requests int
+}
+
+func (c *containsTester) TestMetricFilter(
+	ctx context.Context,
+	params *cloudwatchlogs.TestMetricFilterInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.TestMetricFilterOutput, error) {
+	c.requests++
+	if len(params.LogEventMessages) > fetch.TEST_PATTERN_MAX_MESSAGES {
+		return nil, fmt.Errorf("too many messages")
+	}
+
+	res := &cloudwatchlogs.TestMetricFilterOutput{}
+	for i, message := range params.LogEventMessages {
+		if strings.Contains(message, *params.FilterPattern) {
+			res.Matches = append(res.Matches, types.MetricFilterMatchRecord{
+				EventNumber:     int64(i + 1),
+				EventMessage:    aws.String(message),
+				ExtractedValues: map[string]string{"$length": fmt.Sprint(len(message))},
+			})
+		}
+	}
+	return res, nil
+}
+
+func TestMatchFilterPattern(t *testing.T) {
+	messages := []string{}
+	for i := 0; i < 120; i++ {
+		if i%40 == 0 {
+			messages = append(messages, "ERROR failed")
+		} else {
+			messages = append(messages, fmt.Sprintf("INFO done %d", i))
+		}
+	}
+
+	tester := &containsTester{}
+	matches, err := fetch.MatchFilterPattern(context.Background(), tester, "ERROR", messages)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, tester.requests)
+
+	// Equal messages are matched to their own index
+	assert.Equal(t, map[int]map[string]string{
+		0:  {"$length": "12"},
+		40: {"$length": "12"},
+		80: {"$length": "12"},
+	}, matches)
+}
@@ -10,8 +10,17 @@ const DEFAULT_GROUPS_LIMIT = 20
 
 type GroupsFetchData = FetchData[types.LogGroup]
 
+// Client able to describe log groups, such as *cloudwatchlogs.Client
+type GroupsDescriber interface {
+	DescribeLogGroups(
+		ctx context.Context,
+		params *cloudwatchlogs.DescribeLogGroupsInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+}
+
 type GroupsFetcherClient struct {
-	Client *cloudwatchlogs.Client
+	Client GroupsDescriber
 	Params cloudwatchlogs.DescribeLogGroupsInput
 }
 
@@ -0,0 +1,62 @@
+package fetch_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ravvio/awst/fetch"
+)
+
+// Client returning the given items in pages, failing with err, or a generic
+// error, on the request given by failAt and the following failures-1 ones
+type SliceFetcherClient struct {
+	items    []int
+	limit    *int32
+	offset   int
+	failAt   int
+	failures int
+	err      error
+	page     int
+}
+
+func (c *SliceFetcherClient) Fetch(ctx context.Context) (fetch.FetchData[int], error) {
+	c.page++
+	if c.failAt > 0 && c.page >= c.failAt && c.page < c.failAt+max(c.failures, 1) {
+		if c.err != nil {
+			return fetch.FetchData[int]{}, c.err
+		}
+		return fetch.FetchData[int]{}, fmt.Errorf("page %d failed", c.page)
+	}
+
+	end := min(c.offset+int(*c.limit), len(c.items))
+	data := c.items[c.offset:end]
+	c.offset = end
+
+	var next *string
+	if end < len(c.items) {
+		token := strconv.Itoa(end)
+		next = &token
+	}
+	return fetch.FetchData[int]{Data: data, NextToken: next}, nil
+}
+
+func (c *SliceFetcherClient) RequestLimit() *int32 {
+	return c.limit
+}
+
+func (c *SliceFetcherClient) SetRequestLimit(limit *int32) {
+	c.limit = limit
+}
+
+func (c *SliceFetcherClient) SetNextToken(token *string) {
+	if token != nil {
+		c.offset, _ = strconv.Atoi(*token)
+	}
+}
+
+type SliceFetcher = fetch.Fetcher[*SliceFetcherClient, int]
+
+func sliceFetcher(items []int, requestLimit int32) SliceFetcher {
+	return fetch.NewFetcher(context.Background(), &SliceFetcherClient{items: items}, requestLimit)
+}
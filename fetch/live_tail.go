@@ -0,0 +1,275 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const (
+	DEFAULT_DEDUP_WINDOW = 5 * time.Minute
+	DEFAULT_STABLE_AFTER = 1 * time.Minute
+)
+
+type LiveTailEvent = types.LiveTailSessionLogEvent
+
+// Client able to start live tail sessions and to recover the events they
+// missed with FilterLogEvents
+type LiveTailClient interface {
+	LogsFilterer
+	StartLiveTailStream(
+		ctx context.Context,
+		params *cloudwatchlogs.StartLiveTailInput,
+	) (cloudwatchlogs.StartLiveTailResponseStreamReader, error)
+}
+
+// Live tail client of the cloudwatch logs API
+type CloudwatchLiveTailClient struct {
+	*cloudwatchlogs.Client
+}
+
+func (c CloudwatchLiveTailClient) StartLiveTailStream(
+	ctx context.Context,
+	params *cloudwatchlogs.StartLiveTailInput,
+) (cloudwatchlogs.StartLiveTailResponseStreamReader, error) {
+	out, err := c.StartLiveTail(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return out.GetStream(), nil
+}
+
+var errSessionClosed = errors.New("live tail session closed")
+
+// Live tail session manager.
+// When a session ends, because of its timeout or a network error, a new one is
+// started after a backoff delay and the events ingested in the meantime are
+// recovered with FilterLogEvents, dropping those which were already emitted.
+// The backoff is reset once a session delivers events or stays up for
+// StableAfter, so that sessions failing right after starting back off.
+type LiveTail struct {
+	Client LiveTailClient
+	Params cloudwatchlogs.StartLiveTailInput
+
+	Backoff     Backoff
+	StableAfter time.Duration
+	// Window before the last seen event which is searched again when
+	// recovering missed events
+	DedupWindow time.Duration
+	// Optional client side filter applied to every event
+	Filter func(event *LiveTailEvent) bool
+
+	OnSessionStart func(sessionId string)
+	OnReconnect    func(err error, delay time.Duration)
+
+	// Unix milliseconds of the start of the tail, events before it are not
+	// recovered since they are not part of the tail
+	started   int64
+	lastSeen  int64
+	lastPrune int64
+	seen      map[string]int64
+	// Start of the current session, zero until it is confirmed
+	sessionStart time.Time
+}
+
+func NewLiveTail(
+	client LiveTailClient,
+	params cloudwatchlogs.StartLiveTailInput,
+) *LiveTail {
+	return &LiveTail{
+		Client:      client,
+		Params:      params,
+		Backoff:     NewBackoff(),
+		StableAfter: DEFAULT_STABLE_AFTER,
+		DedupWindow: DEFAULT_DEDUP_WINDOW,
+		seen:        map[string]int64{},
+	}
+}
+
+// Run the live tail sending events to the given channel until the context is
+// done or a non recoverable error occurs
+func (t *LiveTail) Run(ctx context.Context, events chan<- LiveTailEvent) error {
+	t.started = time.Now().UnixMilli()
+	t.lastSeen = t.started
+	t.lastPrune = t.lastSeen
+
+	reconnect := false
+	for {
+		stream, err := t.Client.StartLiveTailStream(ctx, &t.Params)
+		if err == nil {
+			if reconnect {
+				err = t.backfill(ctx, events)
+			}
+			if err == nil {
+				err = t.consume(ctx, stream, events)
+			}
+			stream.Close()
+		}
+		if !t.sessionStart.IsZero() && time.Since(t.sessionStart) >= t.StableAfter {
+			t.Backoff.Reset()
+		}
+		t.sessionStart = time.Time{}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !isRecoverable(err) {
+			return err
+		}
+
+		delay := t.Backoff.Next()
+		if t.OnReconnect != nil {
+			t.OnReconnect(err, delay)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+		reconnect = true
+	}
+}
+
+func (t *LiveTail) consume(
+	ctx context.Context,
+	stream cloudwatchlogs.StartLiveTailResponseStreamReader,
+	events chan<- LiveTailEvent,
+) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-stream.Events():
+			if !ok {
+				if err := stream.Err(); err != nil {
+					return err
+				}
+				return errSessionClosed
+			}
+
+			switch e := event.(type) {
+			case *types.StartLiveTailResponseStreamMemberSessionStart:
+				t.sessionStart = time.Now()
+				if t.OnSessionStart != nil {
+					t.OnSessionStart(aws.ToString(e.Value.SessionId))
+				}
+			case *types.StartLiveTailResponseStreamMemberSessionUpdate:
+				if len(e.Value.SessionResults) > 0 {
+					t.Backoff.Reset()
+				}
+				for _, logEvent := range e.Value.SessionResults {
+					if err := t.emit(ctx, logEvent, events); err != nil {
+						return err
+					}
+				}
+				t.prune()
+			}
+		}
+	}
+}
+
+// Recover events ingested since the last seen one, and not before the tail
+// started, so that events shown before the tail are not repeated
+func (t *LiveTail) backfill(ctx context.Context, events chan<- LiveTailEvent) error {
+	startTime := max(t.lastSeen-t.DedupWindow.Milliseconds(), t.started)
+
+	for _, identifier := range t.Params.LogGroupIdentifiers {
+		params := cloudwatchlogs.FilterLogEventsInput{
+			LogGroupIdentifier: &identifier,
+			StartTime:          &startTime,
+			FilterPattern:      t.Params.LogEventFilterPattern,
+			LogStreamNames:     t.Params.LogStreamNames,
+		}
+		if len(t.Params.LogStreamNamePrefixes) == 1 {
+			params.LogStreamNamePrefix = &t.Params.LogStreamNamePrefixes[0]
+		}
+
+		fetcher := NewLogsFetcher(
+			ctx,
+			&LogsFetcherClient{
+				Client: t.Client,
+				Params: params,
+			},
+		)
+		for fetcher.HasNextPage() {
+			page, err := fetcher.NextPage()
+			if err != nil {
+				return err
+			}
+			for _, event := range page {
+				logEvent := LiveTailEvent{
+					IngestionTime:      event.IngestionTime,
+					LogGroupIdentifier: &identifier,
+					LogStreamName:      event.LogStreamName,
+					Message:            event.Message,
+					Timestamp:          event.Timestamp,
+				}
+				if err := t.emit(ctx, logEvent, events); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Send the event unless it was already emitted
+func (t *LiveTail) emit(
+	ctx context.Context,
+	event LiveTailEvent,
+	events chan<- LiveTailEvent,
+) error {
+	if t.Filter != nil && !t.Filter(&event) {
+		return nil
+	}
+
+	timestamp := aws.ToInt64(event.Timestamp)
+	key := fmt.Sprintf(
+		"%s/%d/%s",
+		aws.ToString(event.LogStreamName),
+		timestamp,
+		aws.ToString(event.Message),
+	)
+	if _, ok := t.seen[key]; ok {
+		return nil
+	}
+	t.seen[key] = timestamp
+	t.lastSeen = max(t.lastSeen, timestamp)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case events <- event:
+		return nil
+	}
+}
+
+// Forget events which are too old to be received again
+func (t *LiveTail) prune() {
+	window := t.DedupWindow.Milliseconds()
+	if t.lastSeen-t.lastPrune < window {
+		return
+	}
+
+	for key, timestamp := range t.seen {
+		if timestamp < t.lastSeen-window {
+			delete(t.seen, key)
+		}
+	}
+	t.lastPrune = t.lastSeen
+}
+
+func isRecoverable(err error) bool {
+	var (
+		accessDenied *types.AccessDeniedException
+		notFound     *types.ResourceNotFoundException
+		invalid      *types.InvalidParameterException
+	)
+	return !errors.As(err, &accessDenied) &&
+		!errors.As(err, &notFound) &&
+		!errors.As(err, &invalid)
+}
@@ -0,0 +1,209 @@
+package fetch_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+// Session of a fake live tail, which sends its events and then ends with err,
+// or fails to start if it has no events
+type fakeSession struct {
+	events []types.StartLiveTailResponseStream
+	err    error
+}
+
+type fakeSessionStream struct {
+	events chan types.StartLiveTailResponseStream
+	err    error
+}
+
+func (s *fakeSessionStream) Events() <-chan types.StartLiveTailResponseStream {
+	return s.events
+}
+
+func (s *fakeSessionStream) Close() error {
+	return nil
+}
+
+func (s *fakeSessionStream) Err() error {
+	return s.err
+}
+
+// Timestamp of events received by the tail
+var tailTimestamp = time.Now().Add(time.Minute).UnixMilli()
+
+// Live tail client starting the given sessions in order, missed events are
+// returned by every FilterLogEvents request
+type fakeLiveTailClient struct {
+	sessions  []fakeSession
+	missed    []types.FilteredLogEvent
+	backfills int
+}
+
+func (c *fakeLiveTailClient) StartLiveTailStream(
+	ctx context.Context,
+	params *cloudwatchlogs.StartLiveTailInput,
+) (cloudwatchlogs.StartLiveTailResponseStreamReader, error) {
+	session := c.sessions[0]
+	c.sessions = c.sessions[1:]
+	if len(session.events) == 0 {
+		return nil, session.err
+	}
+
+	events := make(chan types.StartLiveTailResponseStream, len(session.events))
+	for _, e := range session.events {
+		events <- e
+	}
+	close(events)
+	return &fakeSessionStream{events: events, err: session.err}, nil
+}
+
+func (c *fakeLiveTailClient) FilterLogEvents(
+	ctx context.Context,
+	params *cloudwatchlogs.FilterLogEventsInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	c.backfills++
+	events := []types.FilteredLogEvent{}
+	for _, event := range c.missed {
+		if *event.Timestamp >= aws.ToInt64(params.StartTime) {
+			events = append(events, event)
+		}
+	}
+	return &cloudwatchlogs.FilterLogEventsOutput{Events: events}, nil
+}
+
+func sessionStart() types.StartLiveTailResponseStream {
+	return &types.StartLiveTailResponseStreamMemberSessionStart{
+		Value: types.LiveTailSessionStart{SessionId: aws.String("session")},
+	}
+}
+
+func sessionUpdate(messages ...string) types.StartLiveTailResponseStream {
+	update := &types.StartLiveTailResponseStreamMemberSessionUpdate{}
+	for _, message := range messages {
+		update.Value.SessionResults = append(update.Value.SessionResults, types.LiveTailSessionLogEvent{
+			LogStreamName: aws.String("stream"),
+			Timestamp:     aws.Int64(tailTimestamp),
+			Message:       aws.String(message),
+		})
+	}
+	return update
+}
+
+func runLiveTail(client *fakeLiveTailClient) ([]string, []time.Duration, error) {
+	tail := fetch.NewLiveTail(client, cloudwatchlogs.StartLiveTailInput{
+		LogGroupIdentifiers: []string{"/ecs/api"},
+	})
+	tail.Backoff = fetch.Backoff{Min: time.Millisecond, Max: 4 * time.Millisecond}
+
+	delays := []time.Duration{}
+	tail.OnReconnect = func(err error, delay time.Duration) {
+		delays = append(delays, delay)
+	}
+
+	events := make(chan fetch.LiveTailEvent)
+	done := make(chan error)
+	go func() {
+		done <- tail.Run(context.Background(), events)
+	}()
+
+	messages := []string{}
+	for {
+		select {
+		case event := <-events:
+			messages = append(messages, *event.Message)
+		case err := <-done:
+			return messages, delays, err
+		}
+	}
+}
+
+var errAccessDenied = &types.AccessDeniedException{Message: aws.String("denied")}
+
+func TestLiveTailReconnect(t *testing.T) {
+	client := &fakeLiveTailClient{
+		sessions: []fakeSession{
+			{events: []types.StartLiveTailResponseStream{sessionStart(), sessionUpdate("a", "b")}},
+			{events: []types.StartLiveTailResponseStream{sessionStart(), sessionUpdate("b", "d")}, err: errAccessDenied},
+		},
+		// Events ingested between the sessions, the already emitted ones
+		// are dropped
+		missed: []types.FilteredLogEvent{
+			{LogStreamName: aws.String("stream"), Timestamp: aws.Int64(tailTimestamp), Message: aws.String("b")},
+			{LogStreamName: aws.String("stream"), Timestamp: aws.Int64(tailTimestamp), Message: aws.String("c")},
+		},
+	}
+
+	messages, delays, err := runLiveTail(client)
+	assert.True(t, errors.Is(err, errAccessDenied))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, messages)
+	assert.Len(t, delays, 1)
+	assert.Equal(t, 1, client.backfills)
+}
+
+func TestLiveTailBackfillStart(t *testing.T) {
+	// Events before the tail started, such as those shown by a previous
+	// fetch, are not recovered after an early reconnect
+	client := &fakeLiveTailClient{
+		sessions: []fakeSession{
+			{events: []types.StartLiveTailResponseStream{sessionStart()}},
+			{events: []types.StartLiveTailResponseStream{sessionStart()}, err: errAccessDenied},
+		},
+		missed: []types.FilteredLogEvent{
+			{LogStreamName: aws.String("stream"), Timestamp: aws.Int64(time.Now().Add(-time.Minute).UnixMilli()), Message: aws.String("old")},
+			{LogStreamName: aws.String("stream"), Timestamp: aws.Int64(tailTimestamp), Message: aws.String("new")},
+		},
+	}
+
+	messages, _, err := runLiveTail(client)
+	assert.True(t, errors.Is(err, errAccessDenied))
+	assert.Equal(t, []string{"new"}, messages)
+	assert.Equal(t, 1, client.backfills)
+}
+
+func TestLiveTailBackoff(t *testing.T) {
+	// Sessions ending right after starting keep backing off
+	client := &fakeLiveTailClient{
+		sessions: []fakeSession{
+			{events: []types.StartLiveTailResponseStream{sessionStart()}},
+			{events: []types.StartLiveTailResponseStream{sessionStart()}},
+			{events: []types.StartLiveTailResponseStream{sessionStart()}},
+			{events: []types.StartLiveTailResponseStream{sessionStart()}},
+			{err: errAccessDenied},
+		},
+	}
+	_, delays, err := runLiveTail(client)
+	assert.True(t, errors.Is(err, errAccessDenied))
+	assert.Equal(t, []time.Duration{1, 2, 4, 4}, scaleDelays(delays))
+
+	// Sessions delivering events reset it, the next one without events
+	// backs off again
+	client = &fakeLiveTailClient{
+		sessions: []fakeSession{
+			{events: []types.StartLiveTailResponseStream{sessionStart(), sessionUpdate("a")}},
+			{events: []types.StartLiveTailResponseStream{sessionStart(), sessionUpdate("b")}},
+			{events: []types.StartLiveTailResponseStream{sessionStart()}},
+			{err: errAccessDenied},
+		},
+	}
+	_, delays, err = runLiveTail(client)
+	assert.True(t, errors.Is(err, errAccessDenied))
+	assert.Equal(t, []time.Duration{1, 1, 2}, scaleDelays(delays))
+}
+
+func scaleDelays(delays []time.Duration) []time.Duration {
+	scaled := []time.Duration{}
+	for _, delay := range delays {
+		scaled = append(scaled, delay/time.Millisecond)
+	}
+	return scaled
+}
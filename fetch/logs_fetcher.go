@@ -11,8 +11,17 @@ const DEFAULT_LOG_LIMIT = 10_000
 
 type LogsFetchData = FetchData[types.FilteredLogEvent]
 
+// Client able to filter log events, such as *cloudwatchlogs.Client
+type LogsFilterer interface {
+	FilterLogEvents(
+		ctx context.Context,
+		params *cloudwatchlogs.FilterLogEventsInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.FilterLogEventsOutput, error)
+}
+
 type LogsFetcherClient struct {
-	Client *cloudwatchlogs.Client
+	Client LogsFilterer
 	Params cloudwatchlogs.FilterLogEventsInput
 }
 
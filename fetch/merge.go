@@ -0,0 +1,163 @@
+package fetch
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Errors of the fetchers which failed during a merge, by source index
+type MergeError struct {
+	Sources map[int]error
+}
+
+func (e *MergeError) Error() string {
+	indexes := []int{}
+	for i := range e.Sources {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	errs := []string{}
+	for _, i := range indexes {
+		errs = append(errs, fmt.Sprintf("source %d: %s", i, e.Sources[i]))
+	}
+	return fmt.Sprintf("%d sources failed: %s", len(e.Sources), strings.Join(errs, "; "))
+}
+
+type mergeSource[T any] struct {
+	pages <-chan Page[T]
+	page  []T
+	pos   int
+}
+
+type mergeItem[T any] struct {
+	item   T
+	source int
+}
+
+type mergeHeap[T any] struct {
+	items []mergeItem[T]
+	less  func(a, b T) bool
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.items) }
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.less(a.item, b.item) {
+		return true
+	}
+	if h.less(b.item, a.item) {
+		return false
+	}
+	// Keep equal items in source order
+	return a.source < b.source
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *mergeHeap[T]) Push(x any) { h.items = append(h.items, x.(mergeItem[T])) }
+
+func (h *mergeHeap[T]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items = h.items[:n-1]
+	return item
+}
+
+// Merge the items of fetchers returning ordered pages, calling emit with the
+// index of the source fetcher for each item in order.
+// Pages are fetched concurrently, with at most maxPar requests at a time, and
+// each fetcher holds at most one page ahead of the merge.
+// A fetcher returning an error is dropped from the merge, and the errors of all
+// failed fetchers are returned as a MergeError once the others are consumed.
+// Merging stops at the first error returned by emit.
+func Merge[C FetcherClient[T], T any](
+	fetchers []Fetcher[C, T],
+	maxPar int,
+	less func(a, b T) bool,
+	emit func(source int, item T) error,
+) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	semaphore := make(chan struct{}, max(maxPar, 1))
+
+	sources := make([]*mergeSource[T], len(fetchers))
+	for i := range fetchers {
+		pages := make(chan Page[T], 1)
+		sources[i] = &mergeSource[T]{pages: pages}
+		go fetchPages(&fetchers[i], semaphore, pages, done)
+	}
+
+	h := &mergeHeap[T]{less: less}
+	failed := map[int]error{}
+
+	// Push the next item of the source on the heap, waiting for its next
+	// page if the current one is consumed
+	advance := func(i int) {
+		s := sources[i]
+		for s.pos >= len(s.page) {
+			p, ok := <-s.pages
+			if !ok {
+				return
+			}
+			if p.Err != nil {
+				failed[i] = p.Err
+				return
+			}
+			s.page, s.pos = p.Items, 0
+		}
+		heap.Push(h, mergeItem[T]{item: s.page[s.pos], source: i})
+		s.pos++
+	}
+
+	for i := range sources {
+		advance(i)
+	}
+
+	for h.Len() > 0 {
+		next := heap.Pop(h).(mergeItem[T])
+		if err := emit(next.source, next.item); err != nil {
+			return err
+		}
+		advance(next.source)
+	}
+
+	if len(failed) > 0 {
+		return &MergeError{Sources: failed}
+	}
+	return nil
+}
+
+func fetchPages[C FetcherClient[T], T any](
+	f *Fetcher[C, T],
+	semaphore chan struct{},
+	pages chan<- Page[T],
+	done <-chan struct{},
+) {
+	defer close(pages)
+
+	for f.HasNextPage() {
+		// Acquire semaphore
+		select {
+		case semaphore <- struct{}{}:
+		case <-done:
+			return
+		}
+		data, err := f.NextPage()
+		// Release semaphore
+		<-semaphore
+
+		select {
+		case pages <- Page[T]{Items: data, Err: err}:
+		case <-done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
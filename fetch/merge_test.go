@@ -0,0 +1,89 @@
+package fetch_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ravvio/awst/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	fetchers := []SliceFetcher{
+		sliceFetcher([]int{1, 4, 7, 10, 13}, 2),
+		sliceFetcher([]int{2, 2, 8}, 1),
+		sliceFetcher([]int{}, 3),
+		sliceFetcher([]int{0, 3, 5, 6, 9, 11, 12}, 3),
+	}
+
+	items := []int{}
+	sources := []int{}
+	err := fetch.Merge(
+		fetchers,
+		2,
+		func(a, b int) bool { return a < b },
+		func(source int, item int) error {
+			items = append(items, item)
+			sources = append(sources, source)
+			return nil
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13}, items)
+	assert.Equal(t, []int{3, 0, 1, 1, 3, 0, 3, 3, 0, 1, 3, 0, 3, 3, 0}, sources)
+}
+
+func TestMergeStop(t *testing.T) {
+	fetchers := []SliceFetcher{
+		sliceFetcher([]int{1, 3, 5, 7, 9}, 1),
+		sliceFetcher([]int{2, 4, 6, 8, 10}, 1),
+	}
+
+	stop := fmt.Errorf("stop")
+	items := []int{}
+	err := fetch.Merge(
+		fetchers,
+		1,
+		func(a, b int) bool { return a < b },
+		func(_ int, item int) error {
+			if item > 4 {
+				return stop
+			}
+			items = append(items, item)
+			return nil
+		},
+	)
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []int{1, 2, 3, 4}, items)
+}
+
+func TestMergePartialFailure(t *testing.T) {
+	fetchers := []SliceFetcher{
+		sliceFetcher([]int{1, 3, 5, 7}, 1),
+		fetch.NewFetcher(
+			context.Background(),
+			&SliceFetcherClient{items: []int{2, 4, 6, 8}, failAt: 3},
+			1,
+		),
+	}
+
+	items := []int{}
+	err := fetch.Merge(
+		fetchers,
+		2,
+		func(a, b int) bool { return a < b },
+		func(_ int, item int) error {
+			items = append(items, item)
+			return nil
+		},
+	)
+
+	var mergeErr *fetch.MergeError
+	assert.ErrorAs(t, err, &mergeErr)
+	assert.Len(t, mergeErr.Sources, 1)
+	assert.EqualError(t, mergeErr.Sources[1], "page 3 failed")
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 7}, items)
+}
@@ -0,0 +1,51 @@
+package fetch
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const DEFAULT_METRIC_FILTERS_LIMIT = 50
+
+type MetricFiltersFetchData = FetchData[types.MetricFilter]
+
+type MetricFiltersFetcherClient struct {
+	Client *cloudwatchlogs.Client
+	Params cloudwatchlogs.DescribeMetricFiltersInput
+}
+
+func (c *MetricFiltersFetcherClient) Fetch(ctx context.Context) (MetricFiltersFetchData, error) {
+	res, err := c.Client.DescribeMetricFilters(ctx, &c.Params)
+	if err != nil {
+		return MetricFiltersFetchData{}, err
+	}
+
+	data := MetricFiltersFetchData{
+		Data:      res.MetricFilters,
+		NextToken: res.NextToken,
+	}
+	return data, nil
+}
+
+func (c *MetricFiltersFetcherClient) RequestLimit() *int32 {
+	return c.Params.Limit
+}
+
+func (c *MetricFiltersFetcherClient) SetRequestLimit(limit *int32) {
+	c.Params.Limit = limit
+}
+
+func (c *MetricFiltersFetcherClient) SetNextToken(token *string) {
+	c.Params.NextToken = token
+}
+
+type MetricFiltersFetcher = Fetcher[*MetricFiltersFetcherClient, types.MetricFilter]
+
+func NewMetricFiltersFetcher(
+	ctx context.Context,
+	client *MetricFiltersFetcherClient,
+) MetricFiltersFetcher {
+	return NewFetcher(ctx, client, DEFAULT_METRIC_FILTERS_LIMIT)
+}
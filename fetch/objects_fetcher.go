@@ -0,0 +1,51 @@
+package fetch
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const DEFAULT_OBJECTS_LIMIT = 1000
+
+type ObjectsFetchData = FetchData[types.Object]
+
+type ObjectsFetcherClient struct {
+	Client *s3.Client
+	Params s3.ListObjectsV2Input
+}
+
+func (c *ObjectsFetcherClient) Fetch(ctx context.Context) (ObjectsFetchData, error) {
+	res, err := c.Client.ListObjectsV2(ctx, &c.Params)
+	if err != nil {
+		return ObjectsFetchData{}, err
+	}
+
+	data := ObjectsFetchData{
+		Data:      res.Contents,
+		NextToken: res.NextContinuationToken,
+	}
+	return data, nil
+}
+
+func (c *ObjectsFetcherClient) RequestLimit() *int32 {
+	return c.Params.MaxKeys
+}
+
+func (c *ObjectsFetcherClient) SetRequestLimit(limit *int32) {
+	c.Params.MaxKeys = limit
+}
+
+func (c *ObjectsFetcherClient) SetNextToken(token *string) {
+	c.Params.ContinuationToken = token
+}
+
+type ObjectsFetcher = Fetcher[*ObjectsFetcherClient, types.Object]
+
+func NewObjectsFetcher(
+	ctx context.Context,
+	client *ObjectsFetcherClient,
+) ObjectsFetcher {
+	return NewFetcher(ctx, client, DEFAULT_OBJECTS_LIMIT)
+}
@@ -0,0 +1,143 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const (
+	DEFAULT_QUERY_POLL_INTERVAL = 1 * time.Second
+	// Maximum number of log groups a single Insights query can search
+	QUERY_MAX_GROUPS = 50
+	// Maximum number of rows returned by an Insights query
+	QUERY_MAX_ROWS = 10_000
+)
+
+type QueryResults struct {
+	// Result fields in order of first appearance
+	Fields     []string
+	Rows       []map[string]string
+	Statistics types.QueryStatistics
+}
+
+// Client able to run Insights queries, such as *cloudwatchlogs.Client
+type QueryClient interface {
+	StartQuery(
+		ctx context.Context,
+		params *cloudwatchlogs.StartQueryInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.StartQueryOutput, error)
+	GetQueryResults(
+		ctx context.Context,
+		params *cloudwatchlogs.GetQueryResultsInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.GetQueryResultsOutput, error)
+	StopQuery(
+		ctx context.Context,
+		params *cloudwatchlogs.StopQueryInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.StopQueryOutput, error)
+}
+
+// Logs Insights query, started with StartQuery and polled with
+// GetQueryResults until it is done
+type Query struct {
+	Client QueryClient
+	Params cloudwatchlogs.StartQueryInput
+
+	PollInterval time.Duration
+	OnProgress   func(status types.QueryStatus, statistics *types.QueryStatistics)
+}
+
+func NewQuery(
+	client QueryClient,
+	params cloudwatchlogs.StartQueryInput,
+) *Query {
+	return &Query{
+		Client:       client,
+		Params:       params,
+		PollInterval: DEFAULT_QUERY_POLL_INTERVAL,
+	}
+}
+
+// Run the query and wait for its results.
+// The query is stopped if polling fails or the context is done before it
+// ends, so that it does not keep scanning events.
+func (q *Query) Run(ctx context.Context) (QueryResults, error) {
+	start, err := q.Client.StartQuery(ctx, &q.Params)
+	if err != nil {
+		return QueryResults{}, err
+	}
+
+	ended := false
+	defer func() {
+		if !ended {
+			q.Client.StopQuery(context.Background(), &cloudwatchlogs.StopQueryInput{
+				QueryId: start.QueryId,
+			})
+		}
+	}()
+
+	for {
+		if err := sleep(ctx, q.PollInterval); err != nil {
+			return QueryResults{}, err
+		}
+
+		res, err := q.Client.GetQueryResults(ctx, &cloudwatchlogs.GetQueryResultsInput{
+			QueryId: start.QueryId,
+		})
+		if err != nil {
+			return QueryResults{}, err
+		}
+
+		if q.OnProgress != nil {
+			q.OnProgress(res.Status, res.Statistics)
+		}
+
+		switch res.Status {
+		case types.QueryStatusScheduled, types.QueryStatusRunning:
+			continue
+		case types.QueryStatusComplete:
+			ended = true
+			return queryResults(res), nil
+		default:
+			ended = true
+			return QueryResults{}, fmt.Errorf("query %s", res.Status)
+		}
+	}
+}
+
+func queryResults(res *cloudwatchlogs.GetQueryResultsOutput) QueryResults {
+	results := QueryResults{
+		Fields: []string{},
+		Rows:   []map[string]string{},
+	}
+	if res.Statistics != nil {
+		results.Statistics = *res.Statistics
+	}
+
+	known := map[string]bool{}
+	for _, fields := range res.Results {
+		row := map[string]string{}
+		for _, field := range fields {
+			name := aws.ToString(field.Field)
+			// Internal pointer to the log event
+			if name == "@ptr" {
+				continue
+			}
+			if !known[name] {
+				known[name] = true
+				results.Fields = append(results.Fields, name)
+			}
+			row[name] = aws.ToString(field.Value)
+		}
+		results.Rows = append(results.Rows, row)
+	}
+
+	return results
+}
@@ -0,0 +1,105 @@
+package fetch_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/ravvio/awst/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+// Query client returning the given results in order, the last one is
+// returned again once the others are over
+type fakeQueryClient struct {
+	results []*cloudwatchlogs.GetQueryResultsOutput
+	err     error
+	stopped int
+}
+
+func (c *fakeQueryClient) StartQuery(
+	ctx context.Context,
+	params *cloudwatchlogs.StartQueryInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.StartQueryOutput, error) {
+	return &cloudwatchlogs.StartQueryOutput{QueryId: aws.String("query")}, nil
+}
+
+func (c *fakeQueryClient) GetQueryResults(
+	ctx context.Context,
+	params *cloudwatchlogs.GetQueryResultsInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	res := c.results[0]
+	if len(c.results) > 1 {
+		c.results = c.results[1:]
+	}
+	return res, nil
+}
+
+func (c *fakeQueryClient) StopQuery(
+	ctx context.Context,
+	params *cloudwatchlogs.StopQueryInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.StopQueryOutput, error) {
+	c.stopped++
+	return &cloudwatchlogs.StopQueryOutput{}, nil
+}
+
+func runQuery(ctx context.Context, client *fakeQueryClient) (fetch.QueryResults, error) {
+	query := fetch.NewQuery(client, cloudwatchlogs.StartQueryInput{})
+	query.PollInterval = time.Millisecond
+	return query.Run(ctx)
+}
+
+func TestQuery(t *testing.T) {
+	client := &fakeQueryClient{results: []*cloudwatchlogs.GetQueryResultsOutput{
+		{Status: types.QueryStatusRunning},
+		{
+			Status: types.QueryStatusComplete,
+			Results: [][]types.ResultField{
+				{{Field: aws.String("@timestamp"), Value: aws.String("t1")}, {Field: aws.String("@ptr"), Value: aws.String("p")}},
+				{{Field: aws.String("count"), Value: aws.String("3")}},
+			},
+		},
+	}}
+
+	results, err := runQuery(context.Background(), client)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"@timestamp", "count"}, results.Fields)
+	assert.Equal(t, []map[string]string{{"@timestamp": "t1"}, {"count": "3"}}, results.Rows)
+	assert.Equal(t, 0, client.stopped)
+
+	// Queries which ended on their own are not stopped
+	client = &fakeQueryClient{results: []*cloudwatchlogs.GetQueryResultsOutput{
+		{Status: types.QueryStatusFailed},
+	}}
+	_, err = runQuery(context.Background(), client)
+	assert.Error(t, err)
+	assert.Equal(t, 0, client.stopped)
+}
+
+func TestQueryStop(t *testing.T) {
+	// Polling failures
+	client := &fakeQueryClient{err: errors.New("polling failed")}
+	_, err := runQuery(context.Background(), client)
+	assert.Error(t, err)
+	assert.Equal(t, 1, client.stopped)
+
+	// Context done while the query is running
+	client = &fakeQueryClient{results: []*cloudwatchlogs.GetQueryResultsOutput{
+		{Status: types.QueryStatusRunning},
+	}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = runQuery(ctx, client)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, client.stopped)
+}
@@ -0,0 +1,73 @@
+package fetch
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const DEFAULT_STREAM_EVENTS_LIMIT = 10_000
+
+type StreamEventsFetchData = FetchData[types.OutputLogEvent]
+
+// Client able to get the events of a stream, such as *cloudwatchlogs.Client
+type StreamEventsGetter interface {
+	GetLogEvents(
+		ctx context.Context,
+		params *cloudwatchlogs.GetLogEventsInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.GetLogEventsOutput, error)
+}
+
+// Client of the events of a stream, pages are followed forward if starting
+// from the head of the stream and backward otherwise
+type StreamEventsFetcherClient struct {
+	Client StreamEventsGetter
+	Params cloudwatchlogs.GetLogEventsInput
+}
+
+func (c *StreamEventsFetcherClient) Fetch(ctx context.Context) (StreamEventsFetchData, error) {
+	res, err := c.Client.GetLogEvents(ctx, &c.Params)
+	if err != nil {
+		return StreamEventsFetchData{}, err
+	}
+
+	token := res.NextBackwardToken
+	if aws.ToBool(c.Params.StartFromHead) {
+		token = res.NextForwardToken
+	}
+	// The end of the stream is reached when the same token is returned,
+	// pages before it may be empty
+	if token != nil && c.Params.NextToken != nil && *token == *c.Params.NextToken {
+		token = nil
+	}
+
+	data := StreamEventsFetchData{
+		Data:      res.Events,
+		NextToken: token,
+	}
+	return data, nil
+}
+
+func (c *StreamEventsFetcherClient) RequestLimit() *int32 {
+	return c.Params.Limit
+}
+
+func (c *StreamEventsFetcherClient) SetRequestLimit(limit *int32) {
+	c.Params.Limit = limit
+}
+
+func (c *StreamEventsFetcherClient) SetNextToken(token *string) {
+	c.Params.NextToken = token
+}
+
+type StreamEventsFetcher = Fetcher[*StreamEventsFetcherClient, types.OutputLogEvent]
+
+func NewStreamEventsFetcher(
+	ctx context.Context,
+	client *StreamEventsFetcherClient,
+) StreamEventsFetcher {
+	return NewFetcher(ctx, client, DEFAULT_STREAM_EVENTS_LIMIT)
+}
@@ -0,0 +1,51 @@
+package fetch
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const DEFAULT_STREAMS_LIMIT = 50
+
+type StreamsFetchData = FetchData[types.LogStream]
+
+type StreamsFetcherClient struct {
+	Client *cloudwatchlogs.Client
+	Params cloudwatchlogs.DescribeLogStreamsInput
+}
+
+func (c *StreamsFetcherClient) Fetch(ctx context.Context) (StreamsFetchData, error) {
+	res, err := c.Client.DescribeLogStreams(ctx, &c.Params)
+	if err != nil {
+		return StreamsFetchData{}, err
+	}
+
+	data := StreamsFetchData{
+		Data:      res.LogStreams,
+		NextToken: res.NextToken,
+	}
+	return data, nil
+}
+
+func (c *StreamsFetcherClient) RequestLimit() *int32 {
+	return c.Params.Limit
+}
+
+func (c *StreamsFetcherClient) SetRequestLimit(limit *int32) {
+	c.Params.Limit = limit
+}
+
+func (c *StreamsFetcherClient) SetNextToken(token *string) {
+	c.Params.NextToken = token
+}
+
+type StreamsFetcher = Fetcher[*StreamsFetcherClient, types.LogStream]
+
+func NewStreamsFetcher(
+	ctx context.Context,
+	client *StreamsFetcherClient,
+) StreamsFetcher {
+	return NewFetcher(ctx, client, DEFAULT_STREAMS_LIMIT)
+}
@@ -0,0 +1,51 @@
+package fetch
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const DEFAULT_SUBSCRIPTION_FILTERS_LIMIT = 50
+
+type SubscriptionFiltersFetchData = FetchData[types.SubscriptionFilter]
+
+type SubscriptionFiltersFetcherClient struct {
+	Client *cloudwatchlogs.Client
+	Params cloudwatchlogs.DescribeSubscriptionFiltersInput
+}
+
+func (c *SubscriptionFiltersFetcherClient) Fetch(ctx context.Context) (SubscriptionFiltersFetchData, error) {
+	res, err := c.Client.DescribeSubscriptionFilters(ctx, &c.Params)
+	if err != nil {
+		return SubscriptionFiltersFetchData{}, err
+	}
+
+	data := SubscriptionFiltersFetchData{
+		Data:      res.SubscriptionFilters,
+		NextToken: res.NextToken,
+	}
+	return data, nil
+}
+
+func (c *SubscriptionFiltersFetcherClient) RequestLimit() *int32 {
+	return c.Params.Limit
+}
+
+func (c *SubscriptionFiltersFetcherClient) SetRequestLimit(limit *int32) {
+	c.Params.Limit = limit
+}
+
+func (c *SubscriptionFiltersFetcherClient) SetNextToken(token *string) {
+	c.Params.NextToken = token
+}
+
+type SubscriptionFiltersFetcher = Fetcher[*SubscriptionFiltersFetcherClient, types.SubscriptionFilter]
+
+func NewSubscriptionFiltersFetcher(
+	ctx context.Context,
+	client *SubscriptionFiltersFetcherClient,
+) SubscriptionFiltersFetcher {
+	return NewFetcher(ctx, client, DEFAULT_SUBSCRIPTION_FILTERS_LIMIT)
+}
@@ -0,0 +1,113 @@
+package queries
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Saved Logs Insights query.
+// Query and log group selection can contain placeholders in text/template
+// syntax, such as {{.service}}, filled in with Render.
+type Query struct {
+	Description string            `yaml:"description"`
+	Query       string            `yaml:"query"`
+	Groups      []string          `yaml:"groups"`
+	Prefix      string            `yaml:"prefix"`
+	Pattern     string            `yaml:"pattern"`
+	Since       string            `yaml:"since"`
+	Until       string            `yaml:"until"`
+	Limit       int32             `yaml:"limit"`
+	Params      map[string]string `yaml:"params"`
+}
+
+// Saved queries by name
+type Library = map[string]Query
+
+// Default location of the saved queries file
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "awst", "queries.yaml"), nil
+}
+
+// Load saved queries from a YAML file, a missing file is an empty library
+func Load(path string) (Library, error) {
+	library := Library{}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return library, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, &library); err != nil {
+		return nil, fmt.Errorf("could not parse saved queries file '%s': %w", path, err)
+	}
+	return library, nil
+}
+
+// Fill in the placeholders of the query using its default parameters,
+// overridden by the given ones
+func (q Query) Render(params map[string]string) (Query, error) {
+	values := map[string]string{}
+	for k, v := range q.Params {
+		values[k] = v
+	}
+	for k, v := range params {
+		values[k] = v
+	}
+
+	var err error
+	render := func(text string) string {
+		if err != nil || !strings.Contains(text, "{{") {
+			return text
+		}
+
+		t, e := template.New("query").Option("missingkey=error").Parse(text)
+		if e != nil {
+			err = e
+			return text
+		}
+
+		var buf bytes.Buffer
+		if e := t.Execute(&buf, values); e != nil {
+			err = e
+			return text
+		}
+		return buf.String()
+	}
+
+	q.Query = render(q.Query)
+	q.Prefix = render(q.Prefix)
+	q.Pattern = render(q.Pattern)
+	groups := []string{}
+	for _, group := range q.Groups {
+		groups = append(groups, render(group))
+	}
+	q.Groups = groups
+
+	return q, err
+}
+
+// Parse key=value pairs into parameters
+func ParseParams(pairs []string) (map[string]string, error) {
+	params := map[string]string{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid parameter '%s', expected key=value", pair)
+		}
+		params[key] = value
+	}
+	return params, nil
+}
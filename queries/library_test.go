@@ -0,0 +1,43 @@
+package queries_test
+
+import (
+	"testing"
+
+	"github.com/ravvio/awst/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	q := queries.Query{
+		Query:  `filter service = "{{.service}}" and status >= {{.status}}`,
+		Groups: []string{"/ecs/{{.service}}"},
+		Params: map[string]string{"status": "500"},
+	}
+
+	r, err := q.Render(map[string]string{"service": "api"})
+	assert.NoError(t, err)
+	assert.Equal(t, `filter service = "api" and status >= 500`, r.Query)
+	assert.Equal(t, []string{"/ecs/api"}, r.Groups)
+
+	r, err = q.Render(map[string]string{"service": "api", "status": "400"})
+	assert.NoError(t, err)
+	assert.Equal(t, `filter service = "api" and status >= 400`, r.Query)
+}
+
+func TestRenderMissingParam(t *testing.T) {
+	q := queries.Query{
+		Query: `filter service = "{{.service}}"`,
+	}
+
+	_, err := q.Render(map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestParseParams(t *testing.T) {
+	params, err := queries.ParseParams([]string{"service=api", "filter=a=b"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"service": "api", "filter": "a=b"}, params)
+
+	_, err = queries.ParseParams([]string{"service"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package chart
+
+import (
+	"strings"
+)
+
+var (
+	sparkTicks = []rune("▁▂▃▄▅▆▇█")
+	// Partial blocks of bars, in eighths of a cell
+	barEighths = []rune(" ▏▎▍▌▋▊▉")
+)
+
+const barFull = '█'
+
+// Maximum of the values, at least 1 so it can be used as a scale
+func Max(values []int) int {
+	m := 1
+	for _, v := range values {
+		m = max(m, v)
+	}
+	return m
+}
+
+// Sparkline of the values scaled to the given maximum, with a character per
+// value. Zero values are blank so that gaps stand out.
+func Sparkline(values []int, scale int) string {
+	scale = max(scale, 1)
+
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		tick := (v*len(sparkTicks) - 1) / scale
+		b.WriteRune(sparkTicks[min(tick, len(sparkTicks)-1)])
+	}
+	return b.String()
+}
+
+// Horizontal bar of the value scaled to the given maximum, which fills width
+// cells, with a resolution of an eighth of a cell
+func Bar(value int, scale int, width int) string {
+	scale = max(scale, 1)
+	value = min(max(value, 0), scale)
+
+	eighths := value * width * 8 / scale
+	// Show non zero values with at least a partial block
+	if value > 0 && eighths == 0 {
+		eighths = 1
+	}
+
+	bar := strings.Repeat(string(barFull), eighths/8)
+	if eighths%8 > 0 {
+		bar += string(barEighths[eighths%8])
+	}
+	return bar
+}
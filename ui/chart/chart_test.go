@@ -0,0 +1,28 @@
+package chart_test
+
+import (
+	"testing"
+
+	"github.com/ravvio/awst/ui/chart"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁ ▄█▂", chart.Sparkline([]int{1, 0, 4, 8, 2}, 8))
+	// Values are scaled to the given maximum
+	assert.Equal(t, "▁▄", chart.Sparkline([]int{1, 8}, 16))
+	assert.Equal(t, "", chart.Sparkline([]int{}, 0))
+}
+
+func TestBar(t *testing.T) {
+	assert.Equal(t, "██████████", chart.Bar(100, 100, 10))
+	assert.Equal(t, "█████", chart.Bar(50, 100, 10))
+	assert.Equal(t, "██▌", chart.Bar(25, 100, 10))
+	assert.Equal(t, "▏", chart.Bar(1, 1000, 10))
+	assert.Equal(t, "", chart.Bar(0, 100, 10))
+}
+
+func TestMax(t *testing.T) {
+	assert.Equal(t, 7, chart.Max([]int{3, 7, 1}))
+	assert.Equal(t, 1, chart.Max([]int{0, 0}))
+}
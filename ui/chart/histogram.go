@@ -0,0 +1,67 @@
+package chart
+
+import (
+	"fmt"
+	"time"
+)
+
+// Counts of events of each group in time buckets of the same duration, which
+// are aligned to multiples of their duration like Insights bin()
+type Histogram struct {
+	// Unix milliseconds of the start of the first bucket
+	Start  int64
+	Bucket time.Duration
+	Size   int
+	Counts map[string][]int
+}
+
+// Histogram of the buckets holding the range [since, until], which is empty
+// if until is before since
+func NewHistogram(since int64, until int64, bucket time.Duration) Histogram {
+	width := max(bucket.Milliseconds(), 1)
+	start := since - since%width
+	return Histogram{
+		Start:  start,
+		Bucket: time.Duration(width) * time.Millisecond,
+		Size:   max(int((until-start)/width)+1, 0),
+		Counts: map[string][]int{},
+	}
+}
+
+// Add count events of the group in the bucket holding the timestamp, events
+// out of the histogram range are ignored
+func (h *Histogram) Add(group string, timestamp int64, count int) {
+	i := (timestamp - h.Start) / h.Bucket.Milliseconds()
+	if timestamp < h.Start || i >= int64(h.Size) {
+		return
+	}
+
+	counts, ok := h.Counts[group]
+	if !ok {
+		counts = make([]int, h.Size)
+		h.Counts[group] = counts
+	}
+	counts[i] += count
+}
+
+// Unix milliseconds of the start of the i-th bucket
+func (h *Histogram) BucketStart(i int) int64 {
+	return h.Start + int64(i)*h.Bucket.Milliseconds()
+}
+
+// Insights bin() argument for the bucket duration, in the largest unit which
+// divides it
+func (h *Histogram) Bin() string {
+	switch d := h.Bucket; {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	case d%time.Second == 0:
+		return fmt.Sprintf("%ds", d/time.Second)
+	default:
+		return fmt.Sprintf("%dms", d/time.Millisecond)
+	}
+}
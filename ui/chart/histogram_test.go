@@ -0,0 +1,54 @@
+package chart_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ravvio/awst/ui/chart"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	// From 00:01 to 00:14, in 5m buckets aligned to 00:00
+	minute := time.Minute.Milliseconds()
+	h := chart.NewHistogram(minute, 14*minute, 5*time.Minute)
+
+	assert.Equal(t, int64(0), h.Start)
+	assert.Equal(t, 3, h.Size)
+	assert.Equal(t, 10*minute, h.BucketStart(2))
+
+	h.Add("a", 2*minute, 1)
+	h.Add("a", 4*minute, 2)
+	h.Add("a", 12*minute, 1)
+	h.Add("b", 5*minute, 3)
+	h.Add("b", 20*minute, 1)
+
+	assert.Equal(t, map[string][]int{
+		"a": {3, 0, 1},
+		"b": {0, 3, 0},
+	}, h.Counts)
+}
+
+func TestHistogramEmpty(t *testing.T) {
+	minute := time.Minute.Milliseconds()
+	h := chart.NewHistogram(10*minute, 0, 5*time.Minute)
+	assert.Equal(t, 0, h.Size)
+
+	h.Add("a", 0, 1)
+	assert.Equal(t, map[string][]int{}, h.Counts)
+}
+
+func TestHistogramBin(t *testing.T) {
+	bins := map[time.Duration]string{
+		5 * time.Minute:         "5m",
+		90 * time.Minute:        "90m",
+		2 * time.Hour:           "2h",
+		48 * time.Hour:          "2d",
+		30 * time.Second:        "30s",
+		1500 * time.Millisecond: "1500ms",
+	}
+	for bucket, bin := range bins {
+		h := chart.NewHistogram(0, 0, bucket)
+		assert.Equal(t, bin, h.Bin())
+	}
+}
@@ -45,6 +45,11 @@ var (
 			BorderStyle(lipgloss.ThickBorder()).
 			BorderForeground(Primary).
 			Width(100)
+	WarningStyle = lipgloss.NewStyle().PaddingLeft(1).
+			BorderLeft(true).
+			BorderStyle(lipgloss.ThickBorder()).
+			BorderForeground(Accent).
+			Width(100)
 	HintStyle  = lipgloss.NewStyle().Faint(true).Width(100)
 	ErrorStyle = lipgloss.NewStyle().Faint(true).PaddingLeft(1).
 			BorderLeft(true).
@@ -60,6 +65,16 @@ var (
 	HeaderStyle = lipgloss.NewStyle().Foreground(Primary).Bold(true).Padding(0, 1)
 	RowStyle    = lipgloss.NewStyle().Padding(0, 1)
 
+	JsonKeyStyle     = lipgloss.NewStyle().Foreground(Primary)
+	JsonStringStyle  = lipgloss.NewStyle().Foreground(SuccessFg)
+	JsonNumberStyle  = lipgloss.NewStyle().Foreground(ProgressFg)
+	JsonLiteralStyle = lipgloss.NewStyle().Foreground(Accent).Bold(true)
+
+	LevelFatalStyle = lipgloss.NewStyle().Foreground(ErrorFg).Bold(true)
+	LevelErrorStyle = lipgloss.NewStyle().Foreground(ErrorFg)
+	LevelWarnStyle  = lipgloss.NewStyle().Foreground(Accent)
+	LevelDebugStyle = lipgloss.NewStyle().Foreground(DimFg)
+
 	LogTitle   = lipgloss.NewStyle().Foreground(Primary).PaddingRight(1)
 	LogDate    = lipgloss.NewStyle().Foreground(Secondary).PaddingRight(1)
 	LogContent = lipgloss.NewStyle().PaddingRight(1)
@@ -73,6 +88,24 @@ func PrintError(err string, args ...any) {
 	fmt.Fprintln(os.Stderr, StyleError(err, args...))
 }
 
+func StyleWarning(warning string, args ...any) string {
+	return WarningStyle.Render(fmt.Sprintf(warning, args...))
+}
+
+func PrintWarning(warning string, args ...any) {
+	fmt.Fprintln(os.Stderr, StyleWarning(warning, args...))
+}
+
+func StyleProgress(progress string, args ...any) string {
+	return ProgressStyle.Render(fmt.Sprintf(progress, args...))
+}
+
+// Print progress overwriting the current line, a new line must be printed
+// once progress is done
+func PrintProgress(progress string, args ...any) {
+	fmt.Fprintf(os.Stderr, "\r%s\033[K", StyleProgress(progress, args...))
+}
+
 func StyleHint(hint string, args ...any) string {
 	return HintStyle.Render(fmt.Sprintf(hint, args...))
 }
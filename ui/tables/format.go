@@ -0,0 +1,173 @@
+package tables
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of tables
+const (
+	FormatText     = "text"
+	FormatJson     = "json"
+	FormatCsv      = "csv"
+	FormatTsv      = "tsv"
+	FormatYaml     = "yaml"
+	FormatMarkdown = "markdown"
+)
+
+// Renderer of the active columns of a table
+type Renderer func(columns []Column, rows []Row) (string, error)
+
+var renderers = map[string]Renderer{
+	FormatText:     renderText,
+	FormatJson:     renderJson,
+	FormatCsv:      renderSeparated(','),
+	FormatTsv:      renderSeparated('\t'),
+	FormatYaml:     renderYaml,
+	FormatMarkdown: renderMarkdown,
+}
+
+// Register a renderer for the given format, replacing any existing one
+func RegisterRenderer(format string, r Renderer) {
+	renderers[format] = r
+}
+
+// Available output formats
+func Formats() []string {
+	formats := []string{}
+	for format := range renderers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func (t Table) RenderFormat(format string) (string, error) {
+	r, ok := renderers[format]
+	if !ok {
+		return "", fmt.Errorf("unknown output format '%s', expected one of %s", format, strings.Join(Formats(), ", "))
+	}
+	return r(t.activeColumns(), t.rows)
+}
+
+// Objects with column keys as field names
+func renderJson(columns []Column, rows []Row) (string, error) {
+	var b strings.Builder
+	b.WriteString("[")
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  {")
+		for j, col := range columns {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			key, err := json.Marshal(col.Key)
+			if err != nil {
+				return "", err
+			}
+			value, err := json.Marshal(row[col.Key])
+			if err != nil {
+				return "", err
+			}
+			b.Write(key)
+			b.WriteString(": ")
+			b.Write(value)
+		}
+		b.WriteString("}")
+	}
+	if len(rows) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("]")
+	return b.String(), nil
+}
+
+func renderSeparated(separator rune) Renderer {
+	return func(columns []Column, rows []Row) (string, error) {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		w.Comma = separator
+
+		header := []string{}
+		for _, col := range columns {
+			header = append(header, col.Key)
+		}
+		w.Write(header)
+
+		for _, row := range rows {
+			record := []string{}
+			for _, col := range columns {
+				record = append(record, row[col.Key])
+			}
+			w.Write(record)
+		}
+
+		w.Flush()
+		return strings.TrimSuffix(buf.String(), "\n"), w.Error()
+	}
+}
+
+// Sequence of mappings with column keys as field names
+func renderYaml(columns []Column, rows []Row) (string, error) {
+	doc := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, row := range rows {
+		mapping := &yaml.Node{Kind: yaml.MappingNode}
+		for _, col := range columns {
+			mapping.Content = append(
+				mapping.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: col.Key},
+				&yaml.Node{Kind: yaml.ScalarNode, Value: row[col.Key], Style: yaml.DoubleQuotedStyle},
+			)
+		}
+		doc.Content = append(doc.Content, mapping)
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+func renderMarkdown(columns []Column, rows []Row) (string, error) {
+	escape := func(value string) string {
+		value = strings.ReplaceAll(value, "|", "\\|")
+		return strings.ReplaceAll(value, "\n", "<br>")
+	}
+
+	headers := []string{}
+	separators := []string{}
+	for _, col := range columns {
+		headers = append(headers, escape(col.Title))
+		switch col.Alignment {
+		case Right:
+			separators = append(separators, "---:")
+		case Center:
+			separators = append(separators, ":---:")
+		default:
+			separators = append(separators, "---")
+		}
+	}
+
+	lines := []string{
+		"| " + strings.Join(headers, " | ") + " |",
+		"| " + strings.Join(separators, " | ") + " |",
+	}
+	for _, row := range rows {
+		values := []string{}
+		for _, col := range columns {
+			values = append(values, escape(row[col.Key]))
+		}
+		lines = append(lines, "| "+strings.Join(values, " | ")+" |")
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
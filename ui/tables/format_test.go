@@ -0,0 +1,50 @@
+package tables_test
+
+import (
+	"testing"
+
+	"github.com/ravvio/awst/ui/tables"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTable() tables.Table {
+	columns := []tables.Column{
+		tables.NewColumn("index", "#", true).WithAlignment(tables.Right),
+		tables.NewColumn("name", "Name", true),
+		tables.NewColumn("arn", "Arn", false),
+	}
+	rows := []tables.Row{
+		{"index": "1", "name": "/ecs/api", "arn": "arn:api"},
+		{"index": "2", "name": "/ecs/a|b", "arn": "arn:ab"},
+	}
+	return tables.New(columns).WithRows(rows)
+}
+
+func TestRenderJson(t *testing.T) {
+	s, err := testTable().RenderFormat(tables.FormatJson)
+	assert.NoError(t, err)
+	assert.Equal(t, "[\n  {\"index\": \"1\", \"name\": \"/ecs/api\"},\n  {\"index\": \"2\", \"name\": \"/ecs/a|b\"}\n]", s)
+}
+
+func TestRenderCsvAllColumns(t *testing.T) {
+	s, err := testTable().WithAllColumns().RenderFormat(tables.FormatCsv)
+	assert.NoError(t, err)
+	assert.Equal(t, "index,name,arn\n1,/ecs/api,arn:api\n2,/ecs/a|b,arn:ab", s)
+}
+
+func TestRenderYaml(t *testing.T) {
+	s, err := testTable().RenderFormat(tables.FormatYaml)
+	assert.NoError(t, err)
+	assert.Equal(t, "- index: \"1\"\n  name: \"/ecs/api\"\n- index: \"2\"\n  name: \"/ecs/a|b\"", s)
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	s, err := testTable().RenderFormat(tables.FormatMarkdown)
+	assert.NoError(t, err)
+	assert.Equal(t, "| # | Name |\n| ---: | --- |\n| 1 | /ecs/api |\n| 2 | /ecs/a\\|b |", s)
+}
+
+func TestRenderUnknown(t *testing.T) {
+	_, err := testTable().RenderFormat("xml")
+	assert.Error(t, err)
+}
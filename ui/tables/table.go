@@ -54,32 +54,53 @@ func (t Table) WithRows(rows []Row) Table {
 	return t
 }
 
+// Show all columns, including the ones which are not active
+func (t Table) WithAllColumns() Table {
+	columns := make([]Column, len(t.columns))
+	for i, col := range t.columns {
+		col.Active = true
+		columns[i] = col
+	}
+	t.columns = columns
+	return t
+}
+
+func (t Table) activeColumns() []Column {
+	columns := []Column{}
+	for _, col := range t.columns {
+		if col.Active {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// Render the table as styled text
 func (t Table) Render() string {
+	s, _ := renderText(t.activeColumns(), t.rows)
+	return s
+}
+
+func renderText(columns []Column, rows []Row) (string, error) {
 	aligments := []Alignment{}
 	headers := []string{}
-	for _, col := range t.columns {
-		if !col.Active {
-			continue
-		}
+	for _, col := range columns {
 		headers = append(headers, col.Title)
 		aligments = append(aligments, col.Alignment)
 	}
 
-	rows := [][]string{}
-	for _, rowEntry := range t.rows {
+	textRows := [][]string{}
+	for _, rowEntry := range rows {
 		row := []string{}
-		for _, col := range t.columns {
-			if !col.Active {
-				continue
-			}
+		for _, col := range columns {
 			row = append(row, rowEntry[col.Key])
 		}
-		rows = append(rows, row)
+		textRows = append(textRows, row)
 	}
 
 	lt := table.New().
 		Headers(headers...).
-		Rows(rows...).
+		Rows(textRows...).
 		Border(lipgloss.NormalBorder()).
 		BorderLeft(false).BorderRight(false).BorderTop(false).BorderBottom(false).
 		BorderColumn(false).BorderHeader(false).
@@ -105,5 +126,5 @@ func (t Table) Render() string {
 			return sty
 		})
 
-	return lt.Render()
+	return lt.Render(), nil
 }
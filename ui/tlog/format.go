@@ -0,0 +1,163 @@
+package tlog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Output formats of log events
+const (
+	FormatText   = "text"
+	FormatJson   = "json"
+	FormatLogfmt = "logfmt"
+	FormatCsv    = "csv"
+	FormatRaw    = "raw"
+)
+
+var Formats = []string{FormatText, FormatJson, FormatLogfmt, FormatCsv, FormatRaw}
+
+// Layout of timestamps in machine readable formats
+const RecordTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// Log event with plain fields, used by machine readable formats
+type Record struct {
+	Group         string `json:"group"`
+	Stream        string `json:"stream,omitempty"`
+	Timestamp     string `json:"timestamp"`
+	IngestionTime string `json:"ingestion_time,omitempty"`
+	EventId       string `json:"event_id,omitempty"`
+	Message       string `json:"message"`
+	Context       bool   `json:"context,omitempty"`
+}
+
+func NewRecord(log *Log) Record {
+	return Record{
+		Group:         deref(log.GroupName),
+		Stream:        deref(log.StreamName),
+		Timestamp:     formatRecordTime(log.Timestamp),
+		IngestionTime: formatRecordTime(log.IngestionTime),
+		EventId:       deref(log.EventId),
+		Message:       strings.TrimRight(deref(log.Message), "\n"),
+		Context:       log.Context,
+	}
+}
+
+// Setup a renderer of the given machine readable format
+func NewFormatRenderer(format string, w io.Writer) (Renderer, error) {
+	switch format {
+	case FormatJson:
+		return &JsonRenderer{enc: json.NewEncoder(w)}, nil
+	case FormatLogfmt:
+		return &LogfmtRenderer{w: w}, nil
+	case FormatCsv:
+		return &CsvRenderer{w: csv.NewWriter(w)}, nil
+	case FormatRaw:
+		return &RawRenderer{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown output format '%s', expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+// Renderer of log events as newline delimited JSON
+type JsonRenderer struct {
+	enc *json.Encoder
+}
+
+func (r *JsonRenderer) Render(log *Log) error {
+	return r.enc.Encode(NewRecord(log))
+}
+
+// Renderer of log events as logfmt key=value pairs
+type LogfmtRenderer struct {
+	w io.Writer
+}
+
+func (r *LogfmtRenderer) Render(log *Log) error {
+	record := NewRecord(log)
+	pairs := [][2]string{
+		{"group", record.Group},
+		{"stream", record.Stream},
+		{"timestamp", record.Timestamp},
+		{"ingestion_time", record.IngestionTime},
+		{"event_id", record.EventId},
+		{"message", record.Message},
+	}
+
+	fields := []string{}
+	for _, pair := range pairs {
+		if pair[1] == "" && pair[0] != "message" {
+			continue
+		}
+		fields = append(fields, pair[0]+"="+logfmtValue(pair[1]))
+	}
+
+	_, err := fmt.Fprintln(r.w, strings.Join(fields, " "))
+	return err
+}
+
+// Renderer of log events as CSV rows, preceded by a header row
+type CsvRenderer struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (r *CsvRenderer) Render(log *Log) error {
+	if !r.header {
+		r.header = true
+		r.w.Write([]string{"group", "stream", "timestamp", "ingestion_time", "event_id", "message"})
+	}
+
+	record := NewRecord(log)
+	r.w.Write([]string{
+		record.Group,
+		record.Stream,
+		record.Timestamp,
+		record.IngestionTime,
+		record.EventId,
+		record.Message,
+	})
+	// Flush every row so events are written as they arrive during live tail
+	r.w.Flush()
+	return r.w.Error()
+}
+
+// Renderer of log event messages only
+type RawRenderer struct {
+	w io.Writer
+}
+
+func (r *RawRenderer) Render(log *Log) error {
+	_, err := fmt.Fprintln(r.w, strings.TrimRight(deref(log.Message), "\n"))
+	return err
+}
+
+// Print a separator line between groups of events, as grep does
+func (r *RawRenderer) Separate() error {
+	_, err := fmt.Fprintln(r.w, "--")
+	return err
+}
+
+func logfmtValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"=\\") {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
+
+func formatRecordTime(timestamp *int64) string {
+	if timestamp == nil {
+		return ""
+	}
+	return time.UnixMilli(*timestamp).UTC().Format(RecordTimeLayout)
+}
+
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
+	}
+	return *v
+}
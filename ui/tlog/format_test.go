@@ -0,0 +1,62 @@
+package tlog_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func testLog() *tlog.Log {
+	group := "/ecs/api"
+	stream := "ecs/api/0123"
+	eventId := "42"
+	timestamp := int64(1712916000123)
+	message := "request \"failed\" status=503\n"
+	return &tlog.Log{
+		GroupName:  &group,
+		StreamName: &stream,
+		EventId:    &eventId,
+		Timestamp:  &timestamp,
+		Message:    &message,
+	}
+}
+
+func render(t *testing.T, format string) string {
+	var buf bytes.Buffer
+	r, err := tlog.NewFormatRenderer(format, &buf)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Render(testLog()))
+	return buf.String()
+}
+
+func TestFormatJson(t *testing.T) {
+	assert.Equal(
+		t,
+		`{"group":"/ecs/api","stream":"ecs/api/0123","timestamp":"2024-04-12T10:00:00.123Z","event_id":"42","message":"request \"failed\" status=503"}`+"\n",
+		render(t, tlog.FormatJson),
+	)
+}
+
+func TestFormatLogfmt(t *testing.T) {
+	assert.Equal(
+		t,
+		`group=/ecs/api stream=ecs/api/0123 timestamp=2024-04-12T10:00:00.123Z event_id=42 message="request \"failed\" status=503"`+"\n",
+		render(t, tlog.FormatLogfmt),
+	)
+}
+
+func TestFormatCsv(t *testing.T) {
+	assert.Equal(
+		t,
+		"group,stream,timestamp,ingestion_time,event_id,message\n"+
+			`/ecs/api,ecs/api/0123,2024-04-12T10:00:00.123Z,,42,"request ""failed"" status=503"`+"\n",
+		render(t, tlog.FormatCsv),
+	)
+}
+
+func TestFormatUnknown(t *testing.T) {
+	_, err := tlog.NewFormatRenderer("xml", &bytes.Buffer{})
+	assert.Error(t, err)
+}
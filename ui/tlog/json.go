@@ -0,0 +1,187 @@
+package tlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ravvio/awst/ui/style"
+)
+
+// Formatter of log messages holding a JSON object
+type JsonFormatter struct {
+	KeyStyle     lipgloss.Style
+	StringStyle  lipgloss.Style
+	NumberStyle  lipgloss.Style
+	LiteralStyle lipgloss.Style
+	// Print nested values on multiple indented lines
+	Pretty bool
+	// Show only the given fields as key=value pairs, nested fields can be
+	// selected with dots such as http.status
+	Fields []string
+}
+
+func DefaultJsonFormatter() JsonFormatter {
+	return JsonFormatter{
+		KeyStyle:     style.JsonKeyStyle,
+		StringStyle:  style.JsonStringStyle,
+		NumberStyle:  style.JsonNumberStyle,
+		LiteralStyle: style.JsonLiteralStyle,
+	}
+}
+
+// Format the message if it holds a JSON object, report false otherwise
+func (f *JsonFormatter) Format(message string) (string, bool) {
+	message = strings.TrimSpace(message)
+	if !strings.HasPrefix(message, "{") || !json.Valid([]byte(message)) {
+		return message, false
+	}
+
+	if len(f.Fields) > 0 {
+		return f.formatFields(message), true
+	}
+
+	dec := json.NewDecoder(strings.NewReader(message))
+	dec.UseNumber()
+
+	var b strings.Builder
+	if err := f.writeValue(&b, dec, ""); err != nil {
+		return message, false
+	}
+	return b.String(), true
+}
+
+func (f *JsonFormatter) writeValue(b *strings.Builder, dec *json.Decoder, indent string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		open, end := "{", "}"
+		if t == '[' {
+			open, end = "[", "]"
+		}
+		b.WriteString(open)
+
+		inner := indent + "  "
+		first := true
+		for dec.More() {
+			if !first {
+				b.WriteString(",")
+				if !f.Pretty {
+					b.WriteString(" ")
+				}
+			}
+			first = false
+			if f.Pretty {
+				b.WriteString("\n" + inner)
+			}
+
+			if t == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				b.WriteString(f.KeyStyle.Render(quote(key.(string))))
+				b.WriteString(": ")
+			}
+			if err := f.writeValue(b, dec, inner); err != nil {
+				return err
+			}
+		}
+		// Closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+
+		if f.Pretty && !first {
+			b.WriteString("\n" + indent)
+		}
+		b.WriteString(end)
+	case string:
+		b.WriteString(f.StringStyle.Render(quote(t)))
+	case json.Number:
+		b.WriteString(f.NumberStyle.Render(t.String()))
+	case bool:
+		if t {
+			b.WriteString(f.LiteralStyle.Render("true"))
+		} else {
+			b.WriteString(f.LiteralStyle.Render("false"))
+		}
+	case nil:
+		b.WriteString(f.LiteralStyle.Render("null"))
+	}
+
+	return nil
+}
+
+func (f *JsonFormatter) formatFields(message string) string {
+	dec := json.NewDecoder(strings.NewReader(message))
+	dec.UseNumber()
+
+	var object map[string]any
+	if err := dec.Decode(&object); err != nil {
+		return message
+	}
+
+	pairs := []string{}
+	for _, field := range f.Fields {
+		value, ok := lookup(object, field)
+		if !ok {
+			continue
+		}
+
+		var formatted string
+		switch v := value.(type) {
+		case string:
+			if strings.ContainsAny(v, " \t\n\"=") {
+				formatted = f.StringStyle.Render(quote(v))
+			} else {
+				formatted = f.StringStyle.Render(v)
+			}
+		case json.Number:
+			formatted = f.NumberStyle.Render(v.String())
+		case bool, nil:
+			data, _ := json.Marshal(v)
+			formatted = f.LiteralStyle.Render(string(data))
+		default:
+			data, _ := json.Marshal(v)
+			formatted = string(data)
+		}
+		pairs = append(pairs, f.KeyStyle.Render(field)+"="+formatted)
+	}
+
+	// Messages without any of the fields are shown whole
+	if len(pairs) == 0 {
+		return message
+	}
+	return strings.Join(pairs, " ")
+}
+
+// Find a possibly nested field of a JSON object using a dotted path
+func lookup(object map[string]any, path string) (any, bool) {
+	if value, ok := object[path]; ok {
+		return value, true
+	}
+
+	key, rest, ok := strings.Cut(path, ".")
+	if !ok {
+		return nil, false
+	}
+	nested, ok := object[key].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return lookup(nested, rest)
+}
+
+func quote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,55 @@
+package tlog_test
+
+import (
+	"testing"
+
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/stretchr/testify/assert"
+)
+
+const message = `{"level":"error","msg":"request failed","http":{"status":503},"retry":false}`
+
+func TestJsonCompact(t *testing.T) {
+	f := tlog.DefaultJsonFormatter()
+
+	r, ok := f.Format(message)
+	assert.True(t, ok)
+	assert.Equal(t, `{"level": "error", "msg": "request failed", "http": {"status": 503}, "retry": false}`, r)
+}
+
+func TestJsonPretty(t *testing.T) {
+	f := tlog.DefaultJsonFormatter()
+	f.Pretty = true
+
+	r, ok := f.Format(`{"level":"error","tags":["a"],"empty":{}}`)
+	assert.True(t, ok)
+	assert.Equal(t, "{\n  \"level\": \"error\",\n  \"tags\": [\n    \"a\"\n  ],\n  \"empty\": {}\n}", r)
+}
+
+func TestJsonFields(t *testing.T) {
+	f := tlog.DefaultJsonFormatter()
+	f.Fields = []string{"level", "msg", "http.status", "missing"}
+
+	r, ok := f.Format(message)
+	assert.True(t, ok)
+	assert.Equal(t, `level=error msg="request failed" http.status=503`, r)
+}
+
+func TestJsonFieldsMissing(t *testing.T) {
+	f := tlog.DefaultJsonFormatter()
+	f.Fields = []string{"missing", "http.method"}
+
+	r, ok := f.Format(message)
+	assert.True(t, ok)
+	assert.Equal(t, message, r)
+}
+
+func TestJsonPlain(t *testing.T) {
+	f := tlog.DefaultJsonFormatter()
+
+	_, ok := f.Format("ERROR request failed")
+	assert.False(t, ok)
+
+	_, ok = f.Format("{not json")
+	assert.False(t, ok)
+}
@@ -0,0 +1,191 @@
+package tlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ravvio/awst/ui/style"
+)
+
+// Severity of a log event
+type Level int
+
+const (
+	LevelUnknown Level = iota
+	LevelTrace
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+// Names of each level as found in log messages, the first one is canonical
+var levelNames = map[Level][]string{
+	LevelTrace: {"trace"},
+	LevelDebug: {"debug"},
+	LevelInfo:  {"info"},
+	LevelWarn:  {"warn", "warning"},
+	LevelError: {"error", "err"},
+	LevelFatal: {"fatal", "critical", "panic"},
+}
+
+var DefaultLevelStyles = map[Level]lipgloss.Style{
+	LevelTrace: style.LevelDebugStyle,
+	LevelDebug: style.LevelDebugStyle,
+	LevelWarn:  style.LevelWarnStyle,
+	LevelError: style.LevelErrorStyle,
+	LevelFatal: style.LevelFatalStyle,
+}
+
+func (l Level) String() string {
+	if names, ok := levelNames[l]; ok {
+		return names[0]
+	}
+	return "unknown"
+}
+
+// Parse a level name, case insensitive
+func ParseLevel(name string) (Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for level, names := range levelNames {
+		for _, n := range names {
+			if n == name {
+				return level, nil
+			}
+		}
+	}
+	return LevelUnknown, fmt.Errorf("unknown level '%s', expected trace, debug, info, warn, error or fatal", name)
+}
+
+// Numeric levels of pino and bunyan JSON messages, such as "level":50
+var numericLevels = map[Level]int{
+	LevelTrace: 10,
+	LevelDebug: 20,
+	LevelInfo:  30,
+	LevelWarn:  40,
+	LevelError: 50,
+	LevelFatal: 60,
+}
+
+// Fields holding the level of JSON messages, nested fields are separated by
+// dots
+var jsonLevelFields = []string{"level", "severity", "lvl", "levelname", "loglevel", "log.level"}
+
+var (
+	// Key value pairs such as level=error
+	levelPairPattern = regexp.MustCompile(`(?i)\b(?:level|severity|lvl)[=:]\s*"?([a-z]+)`)
+	// Upper case markers of plain text, logger prefixes and Lambda runtimes
+	// such as ERROR, [WARN] or WARNING:root:
+	levelMarkerPattern = regexp.MustCompile(`\b(TRACE|DEBUG|INFO|WARN|WARNING|ERROR|ERR|FATAL|CRITICAL|PANIC)\b`)
+)
+
+// Only the start of messages is searched for level markers
+const levelSearchLength = 120
+
+// Detect the level of a log message, from the level field of JSON messages or
+// from the first level marker of text messages
+func DetectLevel(message string) Level {
+	message = strings.TrimSpace(message)
+
+	if strings.HasPrefix(message, "{") {
+		if level, ok := detectJsonLevel(message); ok {
+			return level
+		}
+	}
+
+	head := message[:min(len(message), levelSearchLength)]
+	if m := levelPairPattern.FindStringSubmatch(head); m != nil {
+		if level, err := ParseLevel(m[1]); err == nil {
+			return level
+		}
+	}
+	if m := levelMarkerPattern.FindStringSubmatch(head); m != nil {
+		level, _ := ParseLevel(m[1])
+		return level
+	}
+	return LevelUnknown
+}
+
+func detectJsonLevel(message string) (Level, bool) {
+	var object map[string]any
+	if err := json.Unmarshal([]byte(message), &object); err != nil {
+		return LevelUnknown, false
+	}
+
+	for _, field := range jsonLevelFields {
+		switch value := lookupField(object, field).(type) {
+		case string:
+			if level, err := ParseLevel(value); err == nil {
+				return level, true
+			}
+		case float64:
+			// Numeric levels of pino and bunyan
+			for level := LevelFatal; level >= LevelTrace; level-- {
+				if value >= float64(numericLevels[level]) {
+					return level, true
+				}
+			}
+		}
+	}
+	return LevelUnknown, false
+}
+
+func lookupField(object map[string]any, field string) any {
+	var value any = object
+	for _, key := range strings.Split(field, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
+
+// CloudWatch filter pattern matching messages which contain the name of a
+// level at least as severe as the given one, in upper, lower or title case,
+// or its numeric JSON level
+func LevelFilterPattern(min Level) string {
+	terms := []string{}
+	for level := max(min, LevelTrace); level <= LevelFatal; level++ {
+		for _, name := range levelNames[level] {
+			terms = append(
+				terms,
+				"?"+strings.ToUpper(name),
+				"?"+name,
+				"?"+strings.ToUpper(name[:1])+name[1:],
+			)
+		}
+	}
+	for level := max(min, LevelTrace); level <= LevelFatal; level++ {
+		terms = append(terms, fmt.Sprintf(`?"\"level\":%d"`, numericLevels[level]))
+	}
+	return strings.Join(terms, " ")
+}
+
+// Renderer dropping events less severe than a minimum level, or with an
+// unknown level, before rendering them with the next renderer
+type LevelFilter struct {
+	Min  Level
+	Next Renderer
+}
+
+func (f *LevelFilter) Render(log *Log) error {
+	// Context events are shown whatever their level
+	if !log.Context && (log.Message == nil || DetectLevel(*log.Message) < f.Min) {
+		return nil
+	}
+	return f.Next.Render(log)
+}
+
+func (f *LevelFilter) Separate() error {
+	return Separate(f.Next)
+}
+
+func (f *LevelFilter) Flush() error {
+	return Flush(f.Next)
+}
@@ -0,0 +1,62 @@
+package tlog_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLevel(t *testing.T) {
+	cases := map[string]tlog.Level{
+		"ERROR something failed": tlog.LevelError,
+		"2024-04-12 13:00:00,123 WARN [main] c.e.Service - slow request": tlog.LevelWarn,
+		"WARNING:root:disk almost full":                                  tlog.LevelWarn,
+		"[ERROR]\t2024-04-12T13:00:00.000Z\t8f3a\tUnhandled exception":   tlog.LevelError,
+		"2024-04-12T13:00:00.000Z\t8f3a\tINFO\tprocessed 3 records":      tlog.LevelInfo,
+		"2024/04/12 13:00:00 DEBUG cache miss key=user:1":                tlog.LevelDebug,
+		`time=2024-04-12T13:00:00Z level=warn msg="retrying"`:            tlog.LevelWarn,
+		`{"level":"error","msg":"failed"}`:                               tlog.LevelError,
+		`{"severity":"CRITICAL","message":"down"}`:                       tlog.LevelFatal,
+		`{"level":30,"msg":"listening"}`:                                 tlog.LevelInfo,
+		`{"level":60,"msg":"exiting"}`:                                   tlog.LevelFatal,
+		`{"log":{"level":"debug"},"message":"ecs"}`:                      tlog.LevelDebug,
+		"request completed without errors":                               tlog.LevelUnknown,
+		"START RequestId: 8f3a Version: $LATEST":                         tlog.LevelUnknown,
+	}
+	for message, level := range cases {
+		assert.Equal(t, level, tlog.DetectLevel(message), message)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := tlog.ParseLevel("Warning")
+	assert.NoError(t, err)
+	assert.Equal(t, tlog.LevelWarn, level)
+
+	_, err = tlog.ParseLevel("loud")
+	assert.Error(t, err)
+}
+
+func TestLevelFilterPattern(t *testing.T) {
+	assert.Equal(
+		t,
+		`?ERROR ?error ?Error ?ERR ?err ?Err ?FATAL ?fatal ?Fatal ?CRITICAL ?critical ?Critical ?PANIC ?panic ?Panic ?"\"level\":50" ?"\"level\":60"`,
+		tlog.LevelFilterPattern(tlog.LevelError),
+	)
+}
+
+func TestLevelFilter(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := tlog.NewFormatRenderer(tlog.FormatRaw, &buf)
+	assert.NoError(t, err)
+	f := tlog.LevelFilter{Min: tlog.LevelWarn, Next: r}
+
+	for _, message := range []string{"INFO started", "WARN slow", "no level", "ERROR failed"} {
+		log := testLog()
+		log.Message = &message
+		assert.NoError(t, f.Render(log))
+	}
+	assert.Equal(t, "WARN slow\nERROR failed\n", buf.String())
+}
@@ -10,38 +10,154 @@ import (
 
 var (
 	DefaultNameStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("4")).PaddingRight(1)
+	DefaultStreamStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("6")).PaddingRight(1)
 	DefaultTimestampStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("5")).PaddingRight(1)
 	DefaultMessageStyle   = lipgloss.NewStyle().PaddingRight(1)
+	DefaultContextStyle   = lipgloss.NewStyle().Faint(true)
+	DefaultIndent         = "    "
 )
 
 type Log struct {
-	GroupName *string
-	Timestamp *int64
-	Message   *string
+	GroupName     *string
+	StreamName    *string
+	EventId       *string
+	Timestamp     *int64
+	IngestionTime *int64
+	Message       *string
+	// Whether the event is shown as context of a matching event
+	Context bool
 }
 
+// Renderer of log events to some output
+type Renderer interface {
+	Render(log *Log) error
+}
+
+// Renderer which can separate groups of events, such as a matching event
+// and its context from the next one
+type Separator interface {
+	Separate() error
+}
+
+// Separate the following events if the renderer supports it
+func Separate(r Renderer) error {
+	if s, ok := r.(Separator); ok {
+		return s.Separate()
+	}
+	return nil
+}
+
+// Renderer of log events as styled text
 type LogRenderer struct {
 	NameStyle      lipgloss.Style
+	StreamStyle    lipgloss.Style
 	TimestampStyle lipgloss.Style
 	MessageStyle   lipgloss.Style
 	DateFormat     string
+	ShowStream     bool
+	// Formatter of JSON messages, if nil messages are printed as they are
+	Json *JsonFormatter
+	// Styles of text messages by detected level, if nil levels are not detected
+	Levels map[Level]lipgloss.Style
+	// Prefix of the lines after the first one of multi-line messages
+	Indent string
+	// Style of context events, replacing all the other ones
+	ContextStyle lipgloss.Style
 }
 
 func DefaultRenderer() LogRenderer {
 	return LogRenderer{
 		NameStyle:      DefaultNameStyle,
+		StreamStyle:    DefaultStreamStyle,
 		TimestampStyle: DefaultTimestampStyle,
 		MessageStyle:   DefaultMessageStyle,
 		DateFormat:     time.RFC3339,
+		Levels:         DefaultLevelStyles,
+		Indent:         DefaultIndent,
+		ContextStyle:   DefaultContextStyle,
 	}
 }
 
 func (l *LogRenderer) Render(log *Log) error {
+	if log.Context {
+		return l.contextRenderer().render(log)
+	}
+	return l.render(log)
+}
+
+// Print a dimmed separator line between groups of events
+func (l *LogRenderer) Separate() error {
+	_, err := fmt.Println(l.ContextStyle.Render("--"))
+	return err
+}
+
+// Copy of the renderer showing events in the context style only
+func (l *LogRenderer) contextRenderer() *LogRenderer {
+	dim := func(s lipgloss.Style) lipgloss.Style {
+		return s.UnsetForeground().UnsetBold().Inherit(l.ContextStyle)
+	}
+
+	c := *l
+	c.NameStyle = dim(l.NameStyle)
+	c.StreamStyle = dim(l.StreamStyle)
+	c.TimestampStyle = dim(l.TimestampStyle)
+	c.MessageStyle = dim(l.MessageStyle)
+	c.Json = nil
+	c.Levels = nil
+	return &c
+}
+
+func (l *LogRenderer) render(log *Log) error {
+	var stream string
+	if l.ShowStream && log.StreamName != nil {
+		stream = l.StreamStyle.Render(*log.StreamName)
+	}
+
+	var message string
+	if formatted, ok := l.formatJson(*log.Message); ok {
+		message = formatted
+	} else {
+		message = l.renderText(*log.Message)
+	}
+
 	_, err := fmt.Printf(
-		"%s%s%s\n",
+		"%s%s%s%s\n",
 		l.NameStyle.Render(*log.GroupName),
+		stream,
 		l.TimestampStyle.Render(time.UnixMilli(*log.Timestamp).Format(l.DateFormat)),
-		l.MessageStyle.Render(strings.Trim(*log.Message, " \n")),
+		message,
 	)
 	return err
 }
+
+func (l *LogRenderer) formatJson(message string) (string, bool) {
+	if l.Json == nil {
+		return message, false
+	}
+	return l.Json.Format(message)
+}
+
+// Render a text message, indenting the lines after the first one
+func (l *LogRenderer) renderText(message string) string {
+	style := l.messageStyle(message)
+
+	lines := strings.Split(strings.Trim(message, " \n"), "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \r")
+		if i > 0 {
+			line = l.Indent + line
+		}
+		lines[i] = style.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (l *LogRenderer) messageStyle(message string) lipgloss.Style {
+	if l.Levels == nil {
+		return l.MessageStyle
+	}
+	if levelStyle, ok := l.Levels[DetectLevel(message)]; ok {
+		return l.MessageStyle.Inherit(levelStyle)
+	}
+	return l.MessageStyle
+}
@@ -0,0 +1,117 @@
+package tlog
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const DEFAULT_JOIN_WINDOW = 1 * time.Second
+
+// Lines continuing the previous event of a stream, such as stack trace frames
+var DefaultContinuationPattern = regexp.MustCompile(`^(\s+|at |Caused by:|Traceback|\.\.\. \d+ more)`)
+
+// Renderer which can hold events back, flushed once no more events follow
+type Flusher interface {
+	Flush() error
+}
+
+// Flush the renderer if it holds events back
+func Flush(r Renderer) error {
+	if f, ok := r.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+type joinedLog struct {
+	log     Log
+	message string
+	stream  string
+	last    int64
+}
+
+// Renderer joining events matching the continuation pattern into the previous
+// event of the same stream, if it was received within the window.
+// Events are rendered in order by the next renderer once the window of the
+// last joined event has passed, the remaining ones are rendered by Flush.
+type Joiner struct {
+	Pattern *regexp.Regexp
+	Window  time.Duration
+	Next    Renderer
+
+	pending []*joinedLog
+}
+
+func NewJoiner(next Renderer) *Joiner {
+	return &Joiner{
+		Pattern: DefaultContinuationPattern,
+		Window:  DEFAULT_JOIN_WINDOW,
+		Next:    next,
+	}
+}
+
+func (j *Joiner) Render(log *Log) error {
+	var (
+		message   = deref(log.Message)
+		stream    = deref(log.GroupName) + "/" + deref(log.StreamName)
+		timestamp = deref(log.Timestamp)
+	)
+
+	// Render events which cannot be continued anymore
+	for len(j.pending) > 0 && j.pending[0].last+j.Window.Milliseconds() < timestamp {
+		if err := j.renderFirst(); err != nil {
+			return err
+		}
+	}
+
+	if j.Pattern.MatchString(message) {
+		for i := len(j.pending) - 1; i >= 0; i-- {
+			p := j.pending[i]
+			if p.stream != stream {
+				continue
+			}
+			if timestamp-p.last <= j.Window.Milliseconds() {
+				p.message = strings.TrimRight(p.message, "\r\n") + "\n" + message
+				p.last = max(p.last, timestamp)
+				return nil
+			}
+			break
+		}
+	}
+
+	j.pending = append(j.pending, &joinedLog{
+		log:     *log,
+		message: message,
+		stream:  stream,
+		last:    timestamp,
+	})
+	return nil
+}
+
+func (j *Joiner) Flush() error {
+	for len(j.pending) > 0 {
+		if err := j.renderFirst(); err != nil {
+			return err
+		}
+	}
+	return Flush(j.Next)
+}
+
+// Render the events held back before separating the following ones
+func (j *Joiner) Separate() error {
+	for len(j.pending) > 0 {
+		if err := j.renderFirst(); err != nil {
+			return err
+		}
+	}
+	return Separate(j.Next)
+}
+
+func (j *Joiner) renderFirst() error {
+	p := j.pending[0]
+	j.pending = j.pending[1:]
+
+	p.log.Message = &p.message
+	return j.Next.Render(&p.log)
+}
@@ -0,0 +1,56 @@
+package tlog_test
+
+import (
+	"testing"
+
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/stretchr/testify/assert"
+)
+
+type collector struct {
+	messages []string
+}
+
+func (c *collector) Render(log *tlog.Log) error {
+	c.messages = append(c.messages, *log.StreamName+": "+*log.Message)
+	return nil
+}
+
+func streamLog(stream string, timestamp int64, message string) *tlog.Log {
+	group := "/ecs/api"
+	return &tlog.Log{
+		GroupName:  &group,
+		StreamName: &stream,
+		Timestamp:  &timestamp,
+		Message:    &message,
+	}
+}
+
+func TestJoiner(t *testing.T) {
+	c := &collector{}
+	j := tlog.NewJoiner(c)
+
+	logs := []*tlog.Log{
+		streamLog("a", 1000, "Exception in thread \"main\" java.lang.IllegalStateException\n"),
+		streamLog("b", 1000, "INFO request served"),
+		streamLog("a", 1001, "\tat com.example.Main.run(Main.java:10)\n"),
+		streamLog("a", 1001, "Caused by: java.io.IOException\n"),
+		streamLog("b", 1500, "    indented, but of another stream"),
+		streamLog("a", 5000, "    too late to be joined"),
+		streamLog("b", 9000, "INFO done"),
+	}
+	for _, log := range logs {
+		assert.NoError(t, j.Render(log))
+	}
+
+	// Events older than the window are rendered as later ones arrive
+	assert.Equal(t, 3, len(c.messages))
+
+	assert.NoError(t, j.Flush())
+	assert.Equal(t, []string{
+		"a: Exception in thread \"main\" java.lang.IllegalStateException\n\tat com.example.Main.run(Main.java:10)\nCaused by: java.io.IOException\n",
+		"b: INFO request served\n    indented, but of another stream",
+		"a:     too late to be joined",
+		"b: INFO done",
+	}, c.messages)
+}
@@ -0,0 +1,144 @@
+package tlog
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Masks of variable tokens, applied in order so that tokens made of others,
+// such as timestamps of numbers, are masked as a whole
+var templateMasks = []struct {
+	pattern *regexp.Regexp
+	mask    func(match string, submatch []string) string
+}{
+	// Quoted strings, except keys of JSON objects
+	{
+		regexp.MustCompile(`"(?:[^"\\]|\\.)*"(\s*:)?`),
+		func(match string, submatch []string) string {
+			if submatch[1] != "" {
+				return match
+			}
+			return "<str>"
+		},
+	},
+	// Single quoted strings, not apostrophes within words
+	{
+		regexp.MustCompile(`(^|[^\w'])'[^'\n]*'`),
+		func(match string, submatch []string) string {
+			return submatch[1] + "<str>"
+		},
+	},
+	{
+		regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`),
+		mask("<time>"),
+	},
+	{
+		regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`),
+		mask("<uuid>"),
+	},
+	{
+		regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?::\d+)?\b`),
+		mask("<ip>"),
+	},
+	// Hex ids, which must mix digits and letters unless prefixed by 0x
+	{
+		regexp.MustCompile(`(?i)\b(?:0x[0-9a-f]+|[0-9a-f]{8,})\b`),
+		func(match string, submatch []string) string {
+			lower := strings.ToLower(match)
+			if strings.HasPrefix(lower, "0x") ||
+				strings.ContainsAny(lower, "0123456789") && strings.ContainsAny(lower, "abcdef") {
+				return "<hex>"
+			}
+			return match
+		},
+	},
+	{
+		regexp.MustCompile(`\d+(?:\.\d+)?`),
+		mask("<num>"),
+	},
+}
+
+func mask(s string) func(string, []string) string {
+	return func(string, []string) string { return s }
+}
+
+var spacePattern = regexp.MustCompile(`\s+`)
+
+// Template of a log message with variable tokens masked, such as numbers,
+// UUIDs, IPs, hex ids and quoted strings. Only the first line is used, so
+// that joined events are grouped by their first line.
+func Template(message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	for _, m := range templateMasks {
+		line = m.pattern.ReplaceAllStringFunc(line, func(match string) string {
+			return m.mask(match, m.pattern.FindStringSubmatch(match))
+		})
+	}
+	return spacePattern.ReplaceAllString(strings.TrimSpace(line), " ")
+}
+
+// Events sharing a template
+type Pattern struct {
+	Template string
+	Count    int
+	// First event with the template
+	Example *Log
+	// Unix milliseconds of the first and last events with the template
+	FirstSeen int64
+	LastSeen  int64
+}
+
+// Renderer which groups events by template instead of printing them
+type Patterns struct {
+	patterns map[string]*Pattern
+	// Number of rendered events
+	Total int
+}
+
+func NewPatterns() *Patterns {
+	return &Patterns{
+		patterns: map[string]*Pattern{},
+	}
+}
+
+func (p *Patterns) Render(log *Log) error {
+	template := Template(deref(log.Message))
+	timestamp := deref(log.Timestamp)
+	p.Total++
+
+	pattern, ok := p.patterns[template]
+	if !ok {
+		p.patterns[template] = &Pattern{
+			Template:  template,
+			Count:     1,
+			Example:   log,
+			FirstSeen: timestamp,
+			LastSeen:  timestamp,
+		}
+		return nil
+	}
+
+	pattern.Count++
+	pattern.FirstSeen = min(pattern.FirstSeen, timestamp)
+	pattern.LastSeen = max(pattern.LastSeen, timestamp)
+	return nil
+}
+
+// Patterns sorted by count, most frequent first, then by first seen time
+func (p *Patterns) Sorted() []Pattern {
+	patterns := make([]Pattern, 0, len(p.patterns))
+	for _, pattern := range p.patterns {
+		patterns = append(patterns, *pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Count != patterns[j].Count {
+			return patterns[i].Count > patterns[j].Count
+		}
+		if patterns[i].FirstSeen != patterns[j].FirstSeen {
+			return patterns[i].FirstSeen < patterns[j].FirstSeen
+		}
+		return patterns[i].Template < patterns[j].Template
+	})
+	return patterns
+}
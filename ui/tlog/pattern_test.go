@@ -0,0 +1,61 @@
+package tlog_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate(t *testing.T) {
+	cases := map[string]string{
+		"processed 42 records in 1.5s":                                     "processed <num> records in <num>s",
+		"START RequestId: 8f3a2b1c-1d2e-4f5a-9b8c-7d6e5f4a3b2c Version: 1": "START RequestId: <uuid> Version: <num>",
+		"connection from 10.0.12.7:5432 refused":                           "connection from <ip> refused",
+		"object 0x7ffd1a2b freed, trace 5e9c1a0b7f3d":                      "object <hex> freed, trace <hex>",
+		`user "alice" not found in 'admins'`:                               "user <str> not found in <str>",
+		"can't find   user  7":                                             "can't find user <num>",
+		"2024-04-12T13:00:00.123Z ERROR failed":                            "<time> ERROR failed",
+		`{"user":"bob","attempt":3}`:                                       `{"user":<str>,"attempt":<num>}`,
+		"deadbeef is a word, 12345678 a number":                            "deadbeef is a word, <num> a number",
+		"Exception: boom\n\tat Main.run(Main.java:12)":                     "Exception: boom",
+	}
+	for message, template := range cases {
+		assert.Equal(t, template, tlog.Template(message), message)
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	p := tlog.NewPatterns()
+	events := []struct {
+		timestamp int64
+		message   string
+	}{
+		{3, "user 1 logged in"},
+		{1, "user 2 logged in"},
+		{2, "cache miss"},
+		{5, "user 3 logged in"},
+		{4, "cache miss"},
+		{6, "disk full"},
+	}
+	for _, e := range events {
+		assert.NoError(t, p.Render(&tlog.Log{
+			Timestamp: aws.Int64(e.timestamp),
+			Message:   aws.String(e.message),
+		}))
+	}
+
+	patterns := p.Sorted()
+	assert.Equal(t, 6, p.Total)
+	assert.Len(t, patterns, 3)
+
+	assert.Equal(t, "user <num> logged in", patterns[0].Template)
+	assert.Equal(t, 3, patterns[0].Count)
+	assert.Equal(t, "user 1 logged in", *patterns[0].Example.Message)
+	assert.Equal(t, int64(1), patterns[0].FirstSeen)
+	assert.Equal(t, int64(5), patterns[0].LastSeen)
+
+	assert.Equal(t, "cache miss", patterns[1].Template)
+	assert.Equal(t, "disk full", patterns[2].Template)
+}
@@ -1,14 +1,53 @@
 package utils
 
 import (
+	"strings"
+
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/ravvio/awst/ui/tlog"
 )
 
 func LogFromCloudwatchEvent(groupName *string, ev *types.FilteredLogEvent) tlog.Log {
 	return tlog.Log{
-		GroupName: groupName,
-		Timestamp: ev.Timestamp,
-		Message:   ev.Message,
+		GroupName:     groupName,
+		StreamName:    ev.LogStreamName,
+		EventId:       ev.EventId,
+		Timestamp:     ev.Timestamp,
+		IngestionTime: ev.IngestionTime,
+		Message:       ev.Message,
+	}
+}
+
+func LogFromOutputEvent(groupName *string, streamName *string, ev *types.OutputLogEvent) tlog.Log {
+	return tlog.Log{
+		GroupName:     groupName,
+		StreamName:    streamName,
+		Timestamp:     ev.Timestamp,
+		IngestionTime: ev.IngestionTime,
+		Message:       ev.Message,
+	}
+}
+
+func LogFromLiveTailEvent(ev *types.LiveTailSessionLogEvent) tlog.Log {
+	groupName := LogGroupName(*ev.LogGroupIdentifier)
+	return tlog.Log{
+		GroupName:     &groupName,
+		StreamName:    ev.LogStreamName,
+		Timestamp:     ev.Timestamp,
+		IngestionTime: ev.IngestionTime,
+		Message:       ev.Message,
+	}
+}
+
+// Extract the log group name from a log group identifier, which can be
+// the group name, its ARN or the account id followed by the name
+func LogGroupName(identifier string) string {
+	if strings.HasPrefix(identifier, "arn:") {
+		_, name, _ := strings.Cut(identifier, ":log-group:")
+		return strings.TrimSuffix(name, ":*")
+	}
+	if account, name, ok := strings.Cut(identifier, ":"); ok && !strings.HasPrefix(account, "/") {
+		return name
 	}
+	return identifier
 }
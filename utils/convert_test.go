@@ -0,0 +1,22 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/ravvio/awst/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogGroupName(t *testing.T) {
+	cases := map[string]string{
+		"/aws/lambda/api": "/aws/lambda/api",
+		"arn:aws:logs:eu-west-1:123456789012:log-group:/aws/lambda/api:*": "/aws/lambda/api",
+		"arn:aws:logs:eu-west-1:123456789012:log-group:/aws/lambda/api":   "/aws/lambda/api",
+		"123456789012:/ecs/web": "/ecs/web",
+		"/ecs/web:8080":         "/ecs/web:8080",
+		"plain-group":           "plain-group",
+	}
+	for identifier, name := range cases {
+		assert.Equal(t, name, utils.LogGroupName(identifier), identifier)
+	}
+}
@@ -1,12 +1,22 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
 
+	"github.com/aws/smithy-go"
 	"github.com/ravvio/awst/ui/style"
 )
 
+const (
+	// Exit code of commands which completed with partial results
+	EXIT_PARTIAL = 2
+	// Exit code of commands stopped by an interrupt
+	EXIT_INTERRUPTED = 130
+)
+
 func CheckErr(err error) {
 	if err != nil {
 		log.Printf("Error: %v", err)
@@ -14,3 +24,12 @@ func CheckErr(err error) {
 		os.Exit(1)
 	}
 }
+
+// Short description of an error, using only code and message of AWS API errors
+func ErrorReason(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return fmt.Sprintf("%s: %s", apiErr.ErrorCode(), apiErr.ErrorMessage())
+	}
+	return err.Error()
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Client side implementation of the CloudWatch filter pattern syntax for
+// unstructured log events.
+// Supported expressions are
+// * term or "quoted term", which must be contained in the message, quotes
+// and backslashes are escaped with a backslash in quoted terms
+// * ?term, at least one of which must be contained in the message
+// * -term, which must not be contained in the message
+// * %regex%, which must match the message
+// JSON and space delimited patterns are not supported.
+type FilterPattern struct {
+	required []string
+	optional []string
+	excluded []string
+	regex    *regexp.Regexp
+}
+
+func CompileFilterPattern(pattern string) (*FilterPattern, error) {
+	p := &FilterPattern{}
+
+	pattern = strings.TrimSpace(pattern)
+	if strings.HasPrefix(pattern, "{") || strings.HasPrefix(pattern, "[") {
+		return nil, fmt.Errorf("structured filter patterns are not supported")
+	}
+
+	if strings.HasPrefix(pattern, "%") {
+		if len(pattern) < 2 || !strings.HasSuffix(pattern, "%") {
+			return nil, fmt.Errorf("unterminated regular expression in filter pattern")
+		}
+		regex, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, err
+		}
+		p.regex = regex
+		return p, nil
+	}
+
+	for pattern != "" {
+		var term string
+		var kind byte
+		if pattern[0] == '?' || pattern[0] == '-' {
+			kind = pattern[0]
+			pattern = pattern[1:]
+		}
+
+		if strings.HasPrefix(pattern, "\"") {
+			quoted, rest, err := parseQuotedTerm(pattern)
+			if err != nil {
+				return nil, err
+			}
+			term = quoted
+			pattern = rest
+		} else {
+			end := strings.IndexAny(pattern, " \t")
+			if end < 0 {
+				end = len(pattern)
+			}
+			term = pattern[:end]
+			pattern = pattern[end:]
+		}
+		pattern = strings.TrimLeft(pattern, " \t")
+
+		switch kind {
+		case '?':
+			p.optional = append(p.optional, term)
+		case '-':
+			p.excluded = append(p.excluded, term)
+		default:
+			p.required = append(p.required, term)
+		}
+	}
+
+	return p, nil
+}
+
+// Parse the quoted term at the start of the pattern, returning it unescaped
+// along with the rest of the pattern
+func parseQuotedTerm(pattern string) (string, string, error) {
+	var b strings.Builder
+	for i := 1; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			b.WriteByte(pattern[i])
+		case '"':
+			return b.String(), pattern[i+1:], nil
+		default:
+			b.WriteByte(pattern[i])
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quoted term in filter pattern")
+}
+
+func (p *FilterPattern) Match(message string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(message)
+	}
+
+	for _, term := range p.required {
+		if !strings.Contains(message, term) {
+			return false
+		}
+	}
+	for _, term := range p.excluded {
+		if strings.Contains(message, term) {
+			return false
+		}
+	}
+	if len(p.optional) == 0 {
+		return true
+	}
+	for _, term := range p.optional {
+		if strings.Contains(message, term) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/ravvio/awst/ui/tlog"
+	"github.com/ravvio/awst/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterPatternTerms(t *testing.T) {
+	p, err := utils.CompileFilterPattern(`ERROR "request failed" -healthcheck`)
+	assert.NoError(t, err)
+
+	assert.True(t, p.Match("ERROR: request failed for /api"))
+	assert.False(t, p.Match("ERROR: request timed out"))
+	assert.False(t, p.Match("ERROR: request failed for /healthcheck"))
+}
+
+func TestFilterPatternEscapes(t *testing.T) {
+	p, err := utils.CompileFilterPattern(`"say \"hi\"" "C:\\tmp"`)
+	assert.NoError(t, err)
+
+	assert.True(t, p.Match(`say "hi" from C:\tmp`))
+	assert.False(t, p.Match(`say hi from C:\tmp`))
+
+	_, err = utils.CompileFilterPattern(`"unterminated \"`)
+	assert.Error(t, err)
+}
+
+func TestFilterPatternLevels(t *testing.T) {
+	p, err := utils.CompileFilterPattern(tlog.LevelFilterPattern(tlog.LevelError))
+	assert.NoError(t, err)
+
+	assert.True(t, p.Match(`{"level":50,"msg":"failed"}`))
+	assert.True(t, p.Match(`{"level":60,"msg":"exiting"}`))
+	assert.True(t, p.Match("ERROR request failed"))
+	assert.False(t, p.Match(`{"level":30,"msg":"listening"}`))
+	assert.False(t, p.Match(`{"level":40,"msg":"slow"}`))
+	assert.False(t, p.Match(`\ level 50`))
+}
+
+func TestFilterPatternOptional(t *testing.T) {
+	p, err := utils.CompileFilterPattern("?ERROR ?WARN")
+	assert.NoError(t, err)
+
+	assert.True(t, p.Match("WARN disk almost full"))
+	assert.True(t, p.Match("ERROR disk full"))
+	assert.False(t, p.Match("INFO disk usage 10%"))
+}
+
+func TestFilterPatternRegex(t *testing.T) {
+	p, err := utils.CompileFilterPattern("%status=5[0-9]{2}%")
+	assert.NoError(t, err)
+
+	assert.True(t, p.Match("GET /api status=503"))
+	assert.False(t, p.Match("GET /api status=200"))
+}
+
+func TestFilterPatternUnsupported(t *testing.T) {
+	_, err := utils.CompileFilterPattern(`{ $.level = "error" }`)
+	assert.Error(t, err)
+
+	_, err = utils.CompileFilterPattern(`"unterminated`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// Format a size in bytes with a binary unit
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// Format an optional unix timestamp in milliseconds
+func FormatTimestamp(timestamp *int64, layout string) string {
+	if timestamp == nil {
+		return "-"
+	}
+	return time.UnixMilli(*timestamp).Format(layout)
+}
@@ -74,3 +74,14 @@ func ParseDuration(duration string) (int64, error) {
 
 	return t, nil
 }
+
+// Parse an absolute datetime or a duration relative to now into a unix
+// timestamp in milliseconds
+func ParseTimestamp(value string, now time.Time) (int64, error) {
+	if t, err := ParseDatetime(value); err == nil && t.UnixMilli() >= 0 {
+		return t.UnixMilli(), nil
+	} else if d, err := ParseDuration(value); err == nil {
+		return now.UnixMilli() - d, nil
+	}
+	return 0, fmt.Errorf("could not parse timestamp '%s'", value)
+}